@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	echoSwagger "github.com/swaggo/echo-swagger"
+
+	"github.com/the-great-checkout/transactions-crud/internal/controller"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/fx"
+	"github.com/the-great-checkout/transactions-crud/internal/mapper"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+	"github.com/the-great-checkout/transactions-crud/internal/service"
+	"github.com/the-great-checkout/transactions-crud/internal/worker"
+)
+
+// application holds the wired services, controllers and middlewares.
+type application struct {
+	environment Environment
+
+	notificationService   *service.NotificationService
+	outboxService         *service.OutboxService
+	projectionService     *service.ProjectionService
+	idempotencyService    *service.IdempotencyService
+	reconciliationService *service.ReconciliationService
+	authorizationService  *service.AuthorizationService
+	ledgerService         *service.LedgerService
+	limitService          *service.LimitService
+	disputeService        *service.DisputeService
+	settlementService     *service.SettlementService
+	scheduleService       *service.ScheduleService
+	expiryService         *service.ExpiryService
+
+	transactionController   *controller.TransactionController
+	historyController       *controller.HistoryController
+	refundController        *controller.RefundController
+	disputeController       *controller.DisputeController
+	authorizationController *controller.AuthorizationController
+	ledgerController        *controller.LedgerController
+	merchantController      *controller.MerchantController
+	customerController      *controller.CustomerController
+	pricingController       *controller.PricingController
+	reportController        *controller.ReportController
+	riskController          *controller.RiskController
+	reviewController        *controller.ReviewController
+	limitController         *controller.LimitController
+	settlementController    *controller.SettlementController
+	scheduleController      *controller.ScheduleController
+	expiryController        *controller.ExpiryController
+	purgeController         *controller.PurgeController
+	statusController        *controller.StatusController
+	projectionController    *controller.ProjectionController
+
+	idempotencyMiddleware *controller.IdempotencyMiddleware
+	adminMiddleware       *controller.AdminMiddleware
+}
+
+func newApplication(environment Environment) *application {
+	mongo := database.NewMongo(environment.Mongo.URI, environment.Mongo.Database, environment.Mongo.Collection,
+		environment.Migration.LegacyCurrency)
+	postgres := database.NewPostgres(environment.Postgres.DSN, environment.Postgres.Schema, environment.Migration.LegacyCurrency)
+
+	notificationService := service.NewNotificationService(environment.Kafka.Topic, environment.Kafka.Address)
+
+	outboxRepository := repository.NewOutboxRepository(postgres)
+	outboxService := service.NewOutboxService(outboxRepository, notificationService, postgres, environment.Outbox.Retention)
+
+	statusRepository := repository.NewStatusRepository(postgres)
+	transactionRepository := repository.NewTransactionRepository(postgres)
+	historyRepository := repository.NewHistoryRepository(postgres)
+	historyService := service.NewHistoryService(historyRepository, transactionRepository, mapper.NewHistoryMapper())
+
+	ledgerService := service.NewLedgerService(repository.NewLedgerRepository(postgres), mapper.NewLedgerMapper())
+
+	merchantRepository := repository.NewMerchantRepository(postgres)
+	customerRepository := repository.NewCustomerRepository(postgres)
+
+	rateProvider, err := fx.NewFileProvider(environment.FX.RatesFile)
+	if err != nil {
+		panic(err)
+	}
+	if err = service.ValidateSettlementCurrency(environment.FX.SettlementCurrency, environment.FX.RatesFile != ""); err != nil {
+		panic(err)
+	}
+	fxService := service.NewFXService(rateProvider, transactionRepository, statusRepository, environment.FX.SettlementCurrency)
+
+	pricingService := service.NewPricingService(repository.NewPricingRepository(postgres), merchantRepository,
+		mapper.NewPricingMapper())
+
+	riskRepository := repository.NewRiskRepository(postgres)
+	riskService := service.NewRiskService(riskRepository, transactionRepository, mapper.NewRiskMapper(),
+		environment.Risk.ReviewScore, environment.Risk.DeclineScore, environment.FX.SettlementCurrency)
+
+	limitService := service.NewLimitService(repository.NewLimitRepository(postgres), merchantRepository, customerRepository,
+		mapper.NewLimitMapper(), environment.FX.SettlementCurrency)
+
+	transactionMapper := mapper.NewTransactionMapper()
+	transactionService := service.NewTransactionService(postgres, transactionRepository, statusRepository,
+		merchantRepository, customerRepository, outboxService, historyService, ledgerService, pricingService, fxService,
+		riskService, limitService, transactionMapper)
+	transactionController := controller.NewTransactionController(transactionService)
+
+	authorizationService := service.NewAuthorizationService(
+		transactionRepository, transactionService, postgres, postgres, limitService, environment.Authorization.TTL)
+
+	reviewService := service.NewReviewService(postgres, riskRepository, transactionService)
+
+	refundRepository := repository.NewRefundRepository(postgres)
+	disputeRepository := repository.NewDisputeRepository(postgres)
+	refundService := service.NewRefundService(postgres, refundRepository, disputeRepository, transactionService, outboxService,
+		mapper.NewRefundMapper())
+	disputeService := service.NewDisputeService(postgres, postgres, disputeRepository, refundRepository, transactionService,
+		outboxService, mapper.NewDisputeMapper(), environment.Dispute.EvidenceWindow)
+
+	settlementService := service.NewSettlementService(postgres, repository.NewSettlementRepository(postgres), outboxService,
+		mapper.NewSettlementMapper(), transactionMapper)
+
+	scheduleService := service.NewScheduleService(postgres, postgres, repository.NewScheduleRepository(postgres),
+		transactionService, merchantRepository, customerRepository, mapper.NewScheduleMapper())
+
+	if err = service.ValidateExpiryStatuses(environment.Expiry.Statuses); err != nil {
+		panic(err)
+	}
+	expiryService := service.NewExpiryService(postgres, transactionRepository, transactionService,
+		repository.NewJobRunRepository(postgres), mapper.NewJobRunMapper(), environment.Expiry.Statuses, environment.Expiry.TTL)
+
+	projectionRepository := repository.NewProjectionRepository(postgres)
+	documentRepository := repository.NewDocumentRepository(mongo)
+	projectionService := service.NewProjectionService(postgres, projectionRepository, transactionRepository, documentRepository)
+	projectionController := controller.NewProjectionController(projectionService)
+
+	reconciliationService := service.NewReconciliationService(transactionRepository, documentRepository, projectionService)
+
+	idempotencyRepository := repository.NewIdempotencyRepository(postgres)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepository, environment.Idempotency.Retention,
+		environment.Idempotency.Lease)
+	idempotencyMiddleware := controller.NewIdempotencyMiddleware(idempotencyService)
+
+	statusController := controller.NewStatusController(service.NewStatusService(statusRepository, mapper.NewStatusMapper()))
+
+	return &application{
+		environment:             environment,
+		notificationService:     notificationService,
+		outboxService:           outboxService,
+		projectionService:       projectionService,
+		idempotencyService:      idempotencyService,
+		reconciliationService:   reconciliationService,
+		authorizationService:    authorizationService,
+		ledgerService:           ledgerService,
+		limitService:            limitService,
+		disputeService:          disputeService,
+		settlementService:       settlementService,
+		scheduleService:         scheduleService,
+		expiryService:           expiryService,
+		transactionController:   transactionController,
+		historyController:       controller.NewHistoryController(historyService),
+		refundController:        controller.NewRefundController(refundService),
+		disputeController:       controller.NewDisputeController(disputeService),
+		authorizationController: controller.NewAuthorizationController(authorizationService),
+		ledgerController:        controller.NewLedgerController(ledgerService),
+		merchantController: controller.NewMerchantController(
+			service.NewMerchantService(merchantRepository, mapper.NewMerchantMapper())),
+		customerController: controller.NewCustomerController(
+			service.NewCustomerService(customerRepository, mapper.NewCustomerMapper())),
+		purgeController: controller.NewPurgeController(service.NewPurgeService(postgres, transactionRepository, outboxService,
+			transactionMapper, environment.Purge.Retention)),
+		pricingController:     controller.NewPricingController(pricingService),
+		reportController:      controller.NewReportController(fxService),
+		riskController:        controller.NewRiskController(riskService),
+		reviewController:      controller.NewReviewController(reviewService),
+		limitController:       controller.NewLimitController(limitService),
+		settlementController:  controller.NewSettlementController(settlementService),
+		scheduleController:    controller.NewScheduleController(scheduleService),
+		expiryController:      controller.NewExpiryController(expiryService),
+		statusController:      statusController,
+		projectionController:  projectionController,
+		idempotencyMiddleware: idempotencyMiddleware,
+		adminMiddleware:       controller.NewAdminMiddleware(environment.AdminToken),
+	}
+}
+
+func (a *application) routes(e *echo.Echo) {
+	e.Use(middleware.RequestID(), controller.RequestContext)
+
+	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	e.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+
+	v1 := e.Group("/v1")
+
+	a.transactionRoutes(v1)
+
+	v1.POST("/merchants", a.merchantController.CreateHandler)
+	v1.GET("/merchants", a.merchantController.GetAllHandler)
+	v1.GET("/merchants/:merchantID", a.merchantController.GetByIDHandler)
+	v1.PUT("/merchants/:merchantID", a.merchantController.UpdateHandler)
+	v1.DELETE("/merchants/:merchantID", a.merchantController.DeleteHandler)
+	v1.POST("/customers", a.customerController.CreateHandler)
+	v1.GET("/customers", a.customerController.GetAllHandler)
+	v1.GET("/customers/:customerID", a.customerController.GetByIDHandler)
+	v1.PUT("/customers/:customerID", a.customerController.UpdateHandler)
+	v1.DELETE("/customers/:customerID", a.customerController.DeleteHandler)
+	v1.GET("/ledger/accounts", a.ledgerController.GetAccountsHandler)
+	v1.GET("/ledger/accounts/:accountID", a.ledgerController.GetAccountHandler)
+	v1.GET("/ledger/entries", a.ledgerController.GetEntriesHandler)
+	v1.GET("/limits/usage", a.limitController.GetUsageHandler)
+	v1.GET("/reports/totals", a.reportController.TotalsHandler)
+	v1.GET("/settlement-batches", a.settlementController.GetAllHandler)
+	v1.GET("/settlement-batches/:batchID", a.settlementController.GetByIDHandler)
+	v1.GET("/settlement-batches/:batchID/transactions", a.settlementController.GetTransactionsHandler)
+	v1.POST("/schedules", a.scheduleController.CreateHandler, a.idempotencyMiddleware.Handle)
+	v1.GET("/schedules", a.scheduleController.GetAllHandler)
+	v1.GET("/schedules/:scheduleID", a.scheduleController.GetByIDHandler)
+	v1.POST("/schedules/:scheduleID/pause", a.scheduleController.PauseHandler)
+	v1.POST("/schedules/:scheduleID/resume", a.scheduleController.ResumeHandler)
+	v1.POST("/schedules/:scheduleID/cancel", a.scheduleController.CancelHandler)
+	v1.POST("/statuses", a.statusController.CreateHandler)
+	v1.GET("/statuses/:statusID", a.statusController.GetByIDHandler)
+	v1.GET("/statuses", a.statusController.GetAllHandler)
+
+	a.adminRoutes(v1.Group("/admin", a.adminMiddleware.Handle))
+}
+
+// transactionRoutes registers the transaction endpoints and their sub-resources.
+func (a *application) transactionRoutes(v1 *echo.Group) {
+	v1.POST("/transactions", a.transactionController.CreateHandler, a.idempotencyMiddleware.Handle)
+	v1.POST("/transactions/quote", a.transactionController.QuoteHandler)
+	v1.GET("/transactions/:transactionID", a.transactionController.GetByIDHandler)
+	v1.GET("/transactions", a.transactionController.GetAllHandler)
+	v1.PUT("/transactions/:transactionID", a.transactionController.UpdateHandler, a.idempotencyMiddleware.Handle)
+	v1.PATCH("/transactions/:transactionID", a.transactionController.PatchHandler, a.idempotencyMiddleware.Handle)
+	v1.DELETE("/transactions/:transactionID", a.transactionController.DeleteHandler)
+	v1.POST("/transactions/:transactionID/authorize", a.authorizationController.AuthorizeHandler, a.idempotencyMiddleware.Handle)
+	v1.POST("/transactions/:transactionID/capture", a.authorizationController.CaptureHandler, a.idempotencyMiddleware.Handle)
+	v1.POST("/transactions/:transactionID/void", a.authorizationController.VoidHandler, a.idempotencyMiddleware.Handle)
+	v1.POST("/transactions/:transactionID/restore", a.transactionController.RestoreHandler, a.idempotencyMiddleware.Handle)
+	v1.POST("/transactions/:transactionID/split", a.transactionController.SplitHandler, a.idempotencyMiddleware.Handle)
+	v1.GET("/transactions/:transactionID/children", a.transactionController.GetChildrenHandler)
+	v1.GET("/transactions/:transactionID/history", a.historyController.GetByTransactionIDHandler)
+	v1.GET("/transactions/:transactionID/risk", a.riskController.GetAssessmentHandler)
+	v1.POST("/transactions/:transactionID/refunds", a.refundController.CreateHandler, a.idempotencyMiddleware.Handle)
+	v1.GET("/transactions/:transactionID/refunds", a.refundController.GetAllHandler)
+	v1.GET("/transactions/:transactionID/refunds/:refundID", a.refundController.GetByIDHandler)
+	v1.PUT("/transactions/:transactionID/refunds/:refundID", a.refundController.UpdateHandler)
+	v1.POST("/transactions/:transactionID/disputes", a.disputeController.CreateHandler, a.idempotencyMiddleware.Handle)
+	v1.GET("/transactions/:transactionID/disputes", a.disputeController.GetAllHandler)
+	v1.GET("/transactions/:transactionID/disputes/:disputeID", a.disputeController.GetByIDHandler)
+	v1.PUT("/transactions/:transactionID/disputes/:disputeID", a.disputeController.UpdateHandler)
+	v1.POST("/transactions/:transactionID/disputes/:disputeID/evidence", a.disputeController.EvidenceHandler)
+}
+
+// adminRoutes registers the operator endpoints guarded by the admin token.
+func (a *application) adminRoutes(admin *echo.Group) {
+	admin.POST("/projections/transactions", a.projectionController.ResyncHandler)
+	admin.POST("/projections/transactions/:transactionID", a.projectionController.RepairHandler)
+	admin.GET("/ledger/check", a.ledgerController.CheckHandler)
+	admin.POST("/settlement-batches/settle", a.settlementController.SettleHandler)
+	admin.POST("/schedules/run", a.scheduleController.RunHandler)
+	admin.GET("/expiry/last-run", a.expiryController.LastRunHandler)
+	admin.POST("/expiry/run", a.expiryController.RunHandler)
+	admin.POST("/pricing-rules", a.pricingController.CreateHandler)
+	admin.GET("/pricing-rules", a.pricingController.GetAllHandler)
+	admin.GET("/pricing-rules/:ruleID", a.pricingController.GetByIDHandler)
+	admin.PUT("/pricing-rules/:ruleID", a.pricingController.UpdateHandler)
+	admin.DELETE("/pricing-rules/:ruleID", a.pricingController.DeleteHandler)
+	admin.POST("/risk-rules", a.riskController.CreateRuleHandler)
+	admin.GET("/risk-rules", a.riskController.GetRulesHandler)
+	admin.GET("/risk-rules/:ruleID", a.riskController.GetRuleByIDHandler)
+	admin.PUT("/risk-rules/:ruleID", a.riskController.UpdateRuleHandler)
+	admin.DELETE("/risk-rules/:ruleID", a.riskController.DeleteRuleHandler)
+	admin.POST("/limits", a.limitController.CreateHandler)
+	admin.GET("/limits", a.limitController.GetAllHandler)
+	admin.GET("/limits/:limitID", a.limitController.GetByIDHandler)
+	admin.PUT("/limits/:limitID", a.limitController.UpdateHandler)
+	admin.DELETE("/limits/:limitID", a.limitController.DeleteHandler)
+	admin.POST("/transactions/purge", a.purgeController.PurgeHandler)
+	admin.POST("/transactions/:transactionID/approve", a.reviewController.ApproveHandler)
+	admin.POST("/transactions/:transactionID/decline", a.reviewController.DeclineHandler)
+}
+
+func (a *application) startWorkers(ctx context.Context) {
+	go worker.NewPeriodic("outbox relay", a.environment.Outbox.RelayInterval, a.outboxService.Relay).Run(ctx)
+	go worker.NewPeriodic("projection", a.environment.Projection.Interval, a.projectionService.Project).Run(ctx)
+	go worker.NewPeriodic("authorization expiry", a.environment.Authorization.ExpiryInterval,
+		a.authorizationService.ExpireAuthorizations).Run(ctx)
+	go worker.NewPeriodic("dispute expiry", a.environment.Dispute.ExpiryInterval, a.disputeService.ExpireDisputes).Run(ctx)
+	go worker.NewPeriodic("settlement", a.environment.Settlement.Interval, a.settlementService.Settle).Run(ctx)
+	go worker.NewPeriodic("expiry", a.environment.Expiry.Interval, a.expiryService.Expire).Run(ctx)
+	go worker.NewPeriodic("scheduler", a.environment.Schedule.Interval, a.scheduleService.Run).Run(ctx)
+	go worker.NewPeriodic("ledger check", a.environment.Ledger.CheckInterval, a.ledgerService.Verify).Run(ctx)
+	go worker.NewPeriodic("outbox purge", time.Hour, func(ctx context.Context) error {
+		_, err := a.outboxService.PurgeProcessed(ctx)
+		return err
+	}).Run(ctx)
+	go worker.NewPeriodic("limit usage purge", time.Hour, func(ctx context.Context) error {
+		_, err := a.limitService.PurgeUsage(ctx)
+		return err
+	}).Run(ctx)
+	go worker.NewPeriodic("idempotency purge", a.environment.Idempotency.PurgeInterval, func(context.Context) error {
+		_, err := a.idempotencyService.PurgeExpired()
+		return err
+	}).Run(ctx)
+}
+
+// serve runs the HTTP server and the background workers until ctx is cancelled.
+func (a *application) serve(ctx context.Context) error {
+	e := echo.New()
+	a.routes(e)
+	a.startWorkers(ctx)
+
+	go func() {
+		<-ctx.Done()
+		if err := e.Shutdown(context.Background()); err != nil {
+			e.Logger.Error(err)
+		}
+	}()
+
+	err := e.Start(a.environment.Port)
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	return errors.Join(err, a.notificationService.Close())
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const adminTokenHeader = "X-Admin-Token"
+
+// AdminMiddleware restricts routes to callers presenting the configured admin token. Admin routes
+// are disabled when no token is configured.
+type AdminMiddleware struct {
+	token string
+}
+
+func NewAdminMiddleware(token string) *AdminMiddleware {
+	return &AdminMiddleware{
+		token: token,
+	}
+}
+
+func (m *AdminMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.token == "" {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "admin endpoints are disabled"})
+		}
+
+		token := c.Request().Header.Get(adminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) != 1 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid admin token"})
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type AuthorizationService interface {
+	Authorize(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+	Capture(ctx context.Context, id uuid.UUID, amount int64) (*dto.Transaction, error)
+	Void(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+}
+
+type AuthorizationController struct {
+	authorizationService AuthorizationService
+}
+
+func NewAuthorizationController(authorizationService AuthorizationService) *AuthorizationController {
+	return &AuthorizationController{
+		authorizationService: authorizationService,
+	}
+}
+
+// AuthorizeHandler authorizes a transaction
+//
+//	@Summary		Authorize a transaction
+//	@Description	Hold the whole amount of a created or pending transaction until it is captured or voided.
+//	@Description	Authorizations that are not fully captured are voided when they expire.
+//	@Tags			transactions
+//	@Produce		json
+//	@Param			id				path		string	true	"Transaction ID"
+//	@Param			Idempotency-Key	header		string	false	"Replays the original response when retried with the same body"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/authorize [post]
+func (ctrl *AuthorizationController) AuthorizeHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	transactionDTO, err := ctrl.authorizationService.Authorize(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transactionDTO)
+}
+
+// CaptureHandler captures an authorized transaction
+//
+//	@Summary		Capture a transaction
+//	@Description	Capture part or, with a zero or missing amount, all of the remaining authorization.
+//	@Description	The transaction is partially_captured until nothing remains and completed afterwards.
+//	@Tags			transactions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string		true	"Transaction ID"
+//	@Param			capture			body		dto.Capture	false	"Amount to capture in minor units"
+//	@Param			Idempotency-Key	header		string		false	"Replays the original response when retried with the same body"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/capture [post]
+func (ctrl *AuthorizationController) CaptureHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Capture
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	transactionDTO, err := ctrl.authorizationService.Capture(c.Request().Context(), id, input.Amount)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transactionDTO)
+}
+
+// VoidHandler voids the remaining authorization of a transaction
+//
+//	@Summary		Void a transaction
+//	@Description	Release the uncaptured remainder of the authorization. The transaction is voided when
+//	@Description	nothing was captured and completed otherwise.
+//	@Tags			transactions
+//	@Produce		json
+//	@Param			id				path		string	true	"Transaction ID"
+//	@Param			Idempotency-Key	header		string	false	"Replays the original response when retried with the same body"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/void [post]
+func (ctrl *AuthorizationController) VoidHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	transactionDTO, err := ctrl.authorizationService.Void(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transactionDTO)
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type CustomerService interface {
+	Create(ctx context.Context, input *dto.Customer) (*dto.Customer, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.Customer, error)
+	GetAll(ctx context.Context, filter dto.PageFilter) ([]dto.Customer, error)
+	Update(ctx context.Context, id uuid.UUID, input *dto.Customer) (*dto.Customer, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type CustomerController struct {
+	customerService CustomerService
+}
+
+func NewCustomerController(customerService CustomerService) *CustomerController {
+	return &CustomerController{
+		customerService: customerService,
+	}
+}
+
+// CreateHandler creates a new customer
+//
+//	@Summary		Create a customer
+//	@Description	Create a new customer with a name and an optional unique external reference
+//	@Tags			customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			customer	body		dto.Customer	true	"Customer Data"
+//	@Success		201			{object}	dto.Customer
+//	@Failure		400			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/customers [post]
+func (ctrl *CustomerController) CreateHandler(c echo.Context) error {
+	var input dto.Customer
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	customer, err := ctrl.customerService.Create(c.Request().Context(), &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, customer)
+}
+
+// GetByIDHandler retrieves a customer by ID
+//
+//	@Summary		Get a customer by ID
+//	@Description	Retrieve a single customer using its ID
+//	@Tags			customers
+//	@Produce		json
+//	@Param			customerID	path		string	true	"Customer ID"
+//	@Success		200			{object}	dto.Customer
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/customers/{customerID} [get]
+func (ctrl *CustomerController) GetByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("customerID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	customer, err := ctrl.customerService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, customer)
+}
+
+// GetAllHandler retrieves a page of customers
+//
+//	@Summary		List customers
+//	@Description	Retrieve customers ordered by ID, passing the last ID of a page as after for the next one
+//	@Tags			customers
+//	@Produce		json
+//	@Param			after	query		string	false	"ID of the last customer of the previous page"
+//	@Param			limit	query		int		false	"Page size, 50 by default and at most 200"
+//	@Success		200		{array}		dto.Customer
+//	@Failure		400		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/v1/customers [get]
+func (ctrl *CustomerController) GetAllHandler(c echo.Context) error {
+	var filter dto.PageFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	customers, err := ctrl.customerService.GetAll(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, customers)
+}
+
+// UpdateHandler updates a customer by ID
+//
+//	@Summary		Update a customer
+//	@Description	Replace the name, external reference and email of a customer
+//	@Tags			customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			customerID	path		string			true	"Customer ID"
+//	@Param			customer	body		dto.Customer	true	"Customer Data"
+//	@Success		200			{object}	dto.Customer
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/customers/{customerID} [put]
+func (ctrl *CustomerController) UpdateHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("customerID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Customer
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	customer, err := ctrl.customerService.Update(c.Request().Context(), id, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, customer)
+}
+
+// DeleteHandler deletes a customer by ID
+//
+//	@Summary		Delete a customer
+//	@Description	Soft delete a customer. Its transactions keep referencing it, new ones cannot.
+//	@Tags			customers
+//	@Param			customerID	path	string	true	"Customer ID"
+//	@Success		204
+//	@Failure		400	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/customers/{customerID} [delete]
+func (ctrl *CustomerController) DeleteHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("customerID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	if err = ctrl.customerService.Delete(c.Request().Context(), id); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
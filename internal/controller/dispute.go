@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type DisputeService interface {
+	Open(ctx context.Context, transactionID uuid.UUID, input *dto.Dispute) (*dto.Dispute, error)
+	GetByID(ctx context.Context, transactionID, id uuid.UUID) (*dto.Dispute, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]dto.Dispute, error)
+	SubmitEvidence(ctx context.Context, transactionID, id uuid.UUID, files []dto.EvidenceFile) (*dto.Dispute, error)
+	UpdateStatus(ctx context.Context, transactionID, id uuid.UUID, status string) (*dto.Dispute, error)
+}
+
+type DisputeController struct {
+	disputeService DisputeService
+}
+
+func NewDisputeController(disputeService DisputeService) *DisputeController {
+	return &DisputeController{
+		disputeService: disputeService,
+	}
+}
+
+// CreateHandler opens a dispute against a transaction
+//
+//	@Summary		Open a dispute
+//	@Description	Record a chargeback raised against a completed or partially refunded transaction. Open
+//	@Description	disputes and pending or succeeded refunds together may not exceed the collected amount.
+//	@Tags			disputes
+//	@Accept			json
+//	@Produce		json
+//	@Param			transactionID	path		string		true	"Transaction ID"
+//	@Param			dispute			body		dto.Dispute	true	"Dispute Data"
+//	@Param			Idempotency-Key	header		string		false	"Replays the original response when retried with the same body"
+//	@Success		201				{object}	dto.Dispute
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/disputes [post]
+func (ctrl *DisputeController) CreateHandler(c echo.Context) error {
+	transactionID, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Dispute
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	disputeDTO, err := ctrl.disputeService.Open(c.Request().Context(), transactionID, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, disputeDTO)
+}
+
+// GetAllHandler lists the disputes of a transaction
+//
+//	@Summary		List the disputes of a transaction
+//	@Description	Retrieve every dispute of a transaction, oldest first
+//	@Tags			disputes
+//	@Produce		json
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Success		200				{array}		dto.Dispute
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/disputes [get]
+func (ctrl *DisputeController) GetAllHandler(c echo.Context) error {
+	transactionID, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	disputes, err := ctrl.disputeService.GetByTransactionID(c.Request().Context(), transactionID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, disputes)
+}
+
+// GetByIDHandler retrieves a dispute by ID
+//
+//	@Summary		Get a dispute by ID
+//	@Description	Retrieve a single dispute of a transaction with its evidence
+//	@Tags			disputes
+//	@Produce		json
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Param			disputeID		path		string	true	"Dispute ID"
+//	@Success		200				{object}	dto.Dispute
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/disputes/{disputeID} [get]
+func (ctrl *DisputeController) GetByIDHandler(c echo.Context) error {
+	transactionID, id, err := disputeIDs(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	disputeDTO, err := ctrl.disputeService.GetByID(c.Request().Context(), transactionID, id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, disputeDTO)
+}
+
+// EvidenceHandler submits evidence for a dispute
+//
+//	@Summary		Submit dispute evidence
+//	@Description	Attach the metadata of up to 20 evidence files in total to a dispute before its
+//	@Description	evidence deadline. The dispute moves to evidence_submitted.
+//	@Tags			disputes
+//	@Accept			json
+//	@Produce		json
+//	@Param			transactionID	path		string				true	"Transaction ID"
+//	@Param			disputeID		path		string				true	"Dispute ID"
+//	@Param			evidence		body		dto.DisputeEvidence	true	"Evidence files"
+//	@Success		200				{object}	dto.Dispute
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/disputes/{disputeID}/evidence [post]
+func (ctrl *DisputeController) EvidenceHandler(c echo.Context) error {
+	transactionID, id, err := disputeIDs(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.DisputeEvidence
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	disputeDTO, err := ctrl.disputeService.SubmitEvidence(c.Request().Context(), transactionID, id, input.Files)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, disputeDTO)
+}
+
+// UpdateHandler resolves a dispute
+//
+//	@Summary		Resolve a dispute
+//	@Description	Move a dispute to won or lost. Only disputes with evidence can be won. A lost dispute is
+//	@Description	added to the charged back amount of the transaction and reversed in the ledger.
+//	@Tags			disputes
+//	@Accept			json
+//	@Produce		json
+//	@Param			transactionID	path		string		true	"Transaction ID"
+//	@Param			disputeID		path		string		true	"Dispute ID"
+//	@Param			dispute			body		dto.Dispute	true	"Dispute with the new status"
+//	@Success		200				{object}	dto.Dispute
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/disputes/{disputeID} [put]
+func (ctrl *DisputeController) UpdateHandler(c echo.Context) error {
+	transactionID, id, err := disputeIDs(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Dispute
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	disputeDTO, err := ctrl.disputeService.UpdateStatus(c.Request().Context(), transactionID, id, input.Status)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, disputeDTO)
+}
+
+func disputeIDs(c echo.Context) (transactionID, id uuid.UUID, err error) {
+	transactionID, err = uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	id, err = uuid.Parse(c.Param("disputeID"))
+	return transactionID, id, err
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/the-great-checkout/transactions-crud/internal/service"
+)
+
+// errorStatus maps known service errors to an HTTP status, falling back to fallback otherwise.
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrTransactionNotFound), errors.Is(err, service.ErrRefundNotFound),
+		errors.Is(err, service.ErrLedgerAccountNotFound), errors.Is(err, service.ErrMerchantNotFound),
+		errors.Is(err, service.ErrCustomerNotFound), errors.Is(err, service.ErrPricingRuleNotFound),
+		errors.Is(err, service.ErrRiskRuleNotFound), errors.Is(err, service.ErrRiskAssessmentNotFound),
+		errors.Is(err, service.ErrLimitNotFound), errors.Is(err, service.ErrDisputeNotFound),
+		errors.Is(err, service.ErrSettlementBatchNotFound), errors.Is(err, service.ErrScheduleNotFound),
+		errors.Is(err, service.ErrJobNotRun):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrIdempotencyKeyInProgress),
+		errors.Is(err, service.ErrNotRefundable), errors.Is(err, service.ErrAuthorizationExpired),
+		errors.Is(err, service.ErrDuplicateReference), errors.Is(err, service.ErrNotDisputable),
+		errors.Is(err, service.ErrEvidenceDeadlinePassed), errors.Is(err, service.ErrPatchConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrUnknownStatus), errors.Is(err, service.ErrIdempotencyKeyReused),
+		errors.Is(err, service.ErrRefundExceedsAmount), errors.Is(err, service.ErrCaptureExceedsAuthorization),
+		errors.Is(err, service.ErrUnknownMerchant), errors.Is(err, service.ErrUnknownCustomer),
+		errors.Is(err, service.ErrTotalMismatch),
+		errors.Is(err, service.ErrSplitMismatch), errors.Is(err, service.ErrChargesExceedAmount),
+		errors.Is(err, service.ErrRateUnavailable), errors.Is(err, service.ErrAmountLimitExceeded),
+		errors.Is(err, service.ErrDisputeExceedsAmount):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrCountLimitExceeded):
+		return http.StatusTooManyRequests
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type ExpiryService interface {
+	Expire(ctx context.Context) error
+	LastRun(ctx context.Context) (*dto.JobRun, error)
+}
+
+type ExpiryController struct {
+	expiryService ExpiryService
+}
+
+func NewExpiryController(expiryService ExpiryService) *ExpiryController {
+	return &ExpiryController{
+		expiryService: expiryService,
+	}
+}
+
+// LastRunHandler reports the last expiry run
+//
+//	@Summary		Get the last expiry run
+//	@Description	Retrieve when the last sweep of stale transactions ran, how many it expired and the
+//	@Description	error that stopped it, if any
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		200				{object}	dto.JobRun
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/expiry/last-run [get]
+func (ctrl *ExpiryController) LastRunHandler(c echo.Context) error {
+	run, err := ctrl.expiryService.LastRun(c.Request().Context())
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, run)
+}
+
+// RunHandler runs the expiry sweep
+//
+//	@Summary		Run the expiry sweep
+//	@Description	Expire the stale transactions, as the scheduled sweep does
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/expiry/run [post]
+func (ctrl *ExpiryController) RunHandler(c echo.Context) error {
+	if err := ctrl.expiryService.Expire(c.Request().Context()); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
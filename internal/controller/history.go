@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type HistoryService interface {
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]dto.TransactionEvent, error)
+}
+
+type HistoryController struct {
+	historyService HistoryService
+}
+
+func NewHistoryController(historyService HistoryService) *HistoryController {
+	return &HistoryController{
+		historyService: historyService,
+	}
+}
+
+// GetByTransactionIDHandler retrieves the change history of a transaction
+//
+//	@Summary		Get the history of a transaction
+//	@Description	Retrieve every change made to a transaction with its previous and new values, actor and request ID, oldest first
+//	@Tags			transactions
+//	@Produce		json
+//	@Param			id	path		string	true	"Transaction ID"
+//	@Success		200	{array}		dto.TransactionEvent
+//	@Failure		400	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/history [get]
+func (ctrl *HistoryController) GetByTransactionIDHandler(c echo.Context) error {
+	idStr := c.Param("transactionID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	events, err := ctrl.historyService.GetByTransactionID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+type IdempotencyService interface {
+	Begin(key, fingerprint string) (*dto.IdempotentResponse, error)
+	Complete(key string, response *dto.IdempotentResponse) error
+	Release(key string) error
+}
+
+type IdempotencyMiddleware struct {
+	idempotencyService IdempotencyService
+}
+
+func NewIdempotencyMiddleware(idempotencyService IdempotencyService) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyService: idempotencyService,
+	}
+}
+
+// Handle replays the stored response of requests repeating an Idempotency-Key header. Requests
+// without the header are passed through untouched. The key is released for a retry when the request
+// fails before a response was written, panics or answers a server error.
+func (m *IdempotencyMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			return next(c)
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := m.idempotencyService.Begin(key, fingerprint(c.Request(), body))
+		if err != nil {
+			return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+		}
+		if stored != nil {
+			c.Response().Header().Set(idempotentReplayedHeader, "true")
+			return c.JSONBlob(stored.StatusCode, stored.Body)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				m.release(c, key)
+				panic(recovered)
+			}
+		}()
+
+		err = next(c)
+		if (err != nil && !c.Response().Committed) || c.Response().Status >= http.StatusInternalServerError {
+			m.release(c, key)
+			return err
+		}
+
+		response := &dto.IdempotentResponse{StatusCode: c.Response().Status, Body: recorder.body.Bytes()}
+		if completeErr := m.idempotencyService.Complete(key, response); completeErr != nil {
+			c.Logger().Error(completeErr)
+		}
+
+		return err
+	}
+}
+
+func (m *IdempotencyMiddleware) release(c echo.Context, key string) {
+	if err := m.idempotencyService.Release(key); err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// fingerprint identifies a request by method, path and body. JSON bodies are re-encoded so that
+// formatting and key order do not matter.
+func fingerprint(request *http.Request, body []byte) string {
+	var decoded any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&decoded) == nil {
+		canonical, err := json.Marshal(decoded)
+		if err == nil {
+			body = canonical
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
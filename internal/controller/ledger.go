@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type LedgerService interface {
+	GetAccounts(ctx context.Context) ([]dto.LedgerAccount, error)
+	GetAccount(ctx context.Context, id uuid.UUID) (*dto.LedgerAccount, error)
+	GetEntries(ctx context.Context, filter dto.JournalFilter) ([]dto.JournalEntry, error)
+	Check(ctx context.Context) (*dto.LedgerCheck, error)
+}
+
+type LedgerController struct {
+	ledgerService LedgerService
+}
+
+func NewLedgerController(ledgerService LedgerService) *LedgerController {
+	return &LedgerController{
+		ledgerService: ledgerService,
+	}
+}
+
+// GetAccountsHandler lists the ledger accounts
+//
+//	@Summary		List ledger accounts
+//	@Description	Retrieve every ledger account with its balance. Debits are positive and credits negative.
+//	@Tags			ledger
+//	@Produce		json
+//	@Success		200	{array}		dto.LedgerAccount
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/ledger/accounts [get]
+func (ctrl *LedgerController) GetAccountsHandler(c echo.Context) error {
+	accounts, err := ctrl.ledgerService.GetAccounts(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, accounts)
+}
+
+// GetAccountHandler retrieves a ledger account by ID
+//
+//	@Summary		Get a ledger account by ID
+//	@Description	Retrieve a single ledger account with its balance
+//	@Tags			ledger
+//	@Produce		json
+//	@Param			accountID	path		string	true	"Account ID"
+//	@Success		200			{object}	dto.LedgerAccount
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/ledger/accounts/{accountID} [get]
+func (ctrl *LedgerController) GetAccountHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("accountID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	account, err := ctrl.ledgerService.GetAccount(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, account)
+}
+
+// GetEntriesHandler lists journal entries
+//
+//	@Summary		List journal entries
+//	@Description	Retrieve the newest journal entries with their postings, optionally of one transaction or account
+//	@Tags			ledger
+//	@Produce		json
+//	@Param			transaction_id	query		string	false	"Transaction ID"
+//	@Param			account_id		query		string	false	"Account ID"
+//	@Param			limit			query		int		false	"Number of entries, 50 by default and at most 200"
+//	@Success		200				{array}		dto.JournalEntry
+//	@Failure		400				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/ledger/entries [get]
+func (ctrl *LedgerController) GetEntriesHandler(c echo.Context) error {
+	var filter dto.JournalFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	entries, err := ctrl.ledgerService.GetEntries(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// CheckHandler checks the ledger invariants
+//
+//	@Summary		Check the ledger
+//	@Description	List journal entries whose postings do not sum to zero and currencies whose postings do not balance
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		200				{object}	dto.LedgerCheck
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/ledger/check [get]
+func (ctrl *LedgerController) CheckHandler(c echo.Context) error {
+	check, err := ctrl.ledgerService.Check(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, check)
+}
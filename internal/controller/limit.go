@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type LimitService interface {
+	Create(ctx context.Context, input *dto.Limit) (*dto.Limit, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.Limit, error)
+	GetAll(ctx context.Context) ([]dto.Limit, error)
+	GetUsage(ctx context.Context, filter dto.LimitUsageFilter) ([]dto.LimitUsage, error)
+	Update(ctx context.Context, id uuid.UUID, input *dto.Limit) (*dto.Limit, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type LimitController struct {
+	limitService LimitService
+}
+
+func NewLimitController(limitService LimitService) *LimitController {
+	return &LimitController{
+		limitService: limitService,
+	}
+}
+
+// CreateHandler creates a new limit
+//
+//	@Summary		Create a limit
+//	@Description	Create a daily or monthly cap on the number and settlement amount of the transactions a
+//	@Description	merchant or customer creates or captures. Without subject_id it applies to each of them.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string		true	"Admin token"
+//	@Param			limit			body		dto.Limit	true	"Limit Data"
+//	@Success		201				{object}	dto.Limit
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/limits [post]
+func (ctrl *LimitController) CreateHandler(c echo.Context) error {
+	var input dto.Limit
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	limit, err := ctrl.limitService.Create(c.Request().Context(), &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, limit)
+}
+
+// GetByIDHandler retrieves a limit by ID
+//
+//	@Summary		Get a limit by ID
+//	@Description	Retrieve a single limit using its ID
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Param			limitID			path		string	true	"Limit ID"
+//	@Success		200				{object}	dto.Limit
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/limits/{limitID} [get]
+func (ctrl *LimitController) GetByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("limitID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	limit, err := ctrl.limitService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, limit)
+}
+
+// GetAllHandler retrieves all limits
+//
+//	@Summary		List limits
+//	@Description	Retrieve every limit, disabled ones included, in creation order
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		200				{array}		dto.Limit
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/limits [get]
+func (ctrl *LimitController) GetAllHandler(c echo.Context) error {
+	limits, err := ctrl.limitService.GetAll(c.Request().Context())
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, limits)
+}
+
+// UpdateHandler updates a limit by ID
+//
+//	@Summary		Update a limit
+//	@Description	Replace a limit. What was consumed in the current period still counts against it.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string		true	"Admin token"
+//	@Param			limitID			path		string		true	"Limit ID"
+//	@Param			limit			body		dto.Limit	true	"Limit Data"
+//	@Success		200				{object}	dto.Limit
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/limits/{limitID} [put]
+func (ctrl *LimitController) UpdateHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("limitID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Limit
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	limit, err := ctrl.limitService.Update(c.Request().Context(), id, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, limit)
+}
+
+// DeleteHandler deletes a limit by ID
+//
+//	@Summary		Delete a limit
+//	@Description	Delete a limit and stop enforcing it.
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Param			limitID			path	string	true	"Limit ID"
+//	@Success		204
+//	@Failure		400	{object}	map[string]string
+//	@Failure		401	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/limits/{limitID} [delete]
+func (ctrl *LimitController) DeleteHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("limitID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	if err = ctrl.limitService.Delete(c.Request().Context(), id); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetUsageHandler reports the consumption of the limits of a merchant or customer
+//
+//	@Summary		Get limit usage
+//	@Description	Report what a merchant or customer consumed of each of its limits in the current period
+//	@Tags			limits
+//	@Produce		json
+//	@Param			merchant_id	query		string	false	"Merchant ID"
+//	@Param			customer_id	query		string	false	"Customer ID"
+//	@Success		200			{array}		dto.LimitUsage
+//	@Failure		400			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/limits/usage [get]
+func (ctrl *LimitController) GetUsageHandler(c echo.Context) error {
+	var filter dto.LimitUsageFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	usage, err := ctrl.limitService.GetUsage(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
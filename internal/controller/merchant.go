@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type MerchantService interface {
+	Create(ctx context.Context, input *dto.Merchant) (*dto.Merchant, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.Merchant, error)
+	GetAll(ctx context.Context, filter dto.PageFilter) ([]dto.Merchant, error)
+	Update(ctx context.Context, id uuid.UUID, input *dto.Merchant) (*dto.Merchant, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type MerchantController struct {
+	merchantService MerchantService
+}
+
+func NewMerchantController(merchantService MerchantService) *MerchantController {
+	return &MerchantController{
+		merchantService: merchantService,
+	}
+}
+
+// CreateHandler creates a new merchant
+//
+//	@Summary		Create a merchant
+//	@Description	Create a new merchant with a name and an optional unique external reference
+//	@Tags			merchants
+//	@Accept			json
+//	@Produce		json
+//	@Param			merchant	body		dto.Merchant	true	"Merchant Data"
+//	@Success		201			{object}	dto.Merchant
+//	@Failure		400			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/merchants [post]
+func (ctrl *MerchantController) CreateHandler(c echo.Context) error {
+	var input dto.Merchant
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	merchant, err := ctrl.merchantService.Create(c.Request().Context(), &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, merchant)
+}
+
+// GetByIDHandler retrieves a merchant by ID
+//
+//	@Summary		Get a merchant by ID
+//	@Description	Retrieve a single merchant using its ID
+//	@Tags			merchants
+//	@Produce		json
+//	@Param			merchantID	path		string	true	"Merchant ID"
+//	@Success		200			{object}	dto.Merchant
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/merchants/{merchantID} [get]
+func (ctrl *MerchantController) GetByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("merchantID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	merchant, err := ctrl.merchantService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, merchant)
+}
+
+// GetAllHandler retrieves a page of merchants
+//
+//	@Summary		List merchants
+//	@Description	Retrieve merchants ordered by ID, passing the last ID of a page as after for the next one
+//	@Tags			merchants
+//	@Produce		json
+//	@Param			after	query		string	false	"ID of the last merchant of the previous page"
+//	@Param			limit	query		int		false	"Page size, 50 by default and at most 200"
+//	@Success		200		{array}		dto.Merchant
+//	@Failure		400		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/v1/merchants [get]
+func (ctrl *MerchantController) GetAllHandler(c echo.Context) error {
+	var filter dto.PageFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	merchants, err := ctrl.merchantService.GetAll(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, merchants)
+}
+
+// UpdateHandler updates a merchant by ID
+//
+//	@Summary		Update a merchant
+//	@Description	Replace the name, external reference and email of a merchant
+//	@Tags			merchants
+//	@Accept			json
+//	@Produce		json
+//	@Param			merchantID	path		string			true	"Merchant ID"
+//	@Param			merchant	body		dto.Merchant	true	"Merchant Data"
+//	@Success		200			{object}	dto.Merchant
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/merchants/{merchantID} [put]
+func (ctrl *MerchantController) UpdateHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("merchantID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Merchant
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	merchant, err := ctrl.merchantService.Update(c.Request().Context(), id, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, merchant)
+}
+
+// DeleteHandler deletes a merchant by ID
+//
+//	@Summary		Delete a merchant
+//	@Description	Soft delete a merchant. Its transactions keep referencing it, new ones cannot.
+//	@Tags			merchants
+//	@Param			merchantID	path	string	true	"Merchant ID"
+//	@Success		204
+//	@Failure		400	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/merchants/{merchantID} [delete]
+func (ctrl *MerchantController) DeleteHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("merchantID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	if err = ctrl.merchantService.Delete(c.Request().Context(), id); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
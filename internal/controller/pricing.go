@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type PricingService interface {
+	Create(ctx context.Context, input *dto.PricingRule) (*dto.PricingRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.PricingRule, error)
+	GetAll(ctx context.Context) ([]dto.PricingRule, error)
+	Update(ctx context.Context, id uuid.UUID, input *dto.PricingRule) (*dto.PricingRule, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type PricingController struct {
+	pricingService PricingService
+}
+
+func NewPricingController(pricingService PricingService) *PricingController {
+	return &PricingController{
+		pricingService: pricingService,
+	}
+}
+
+// CreateHandler creates a new pricing rule
+//
+//	@Summary		Create a pricing rule
+//	@Description	Create a percentage, fixed or tiered fee or tax rule, optionally limited to a currency and a
+//	@Description	merchant. Merchant rules of a kind replace the general rules of that kind for the merchant.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string			true	"Admin token"
+//	@Param			rule			body		dto.PricingRule	true	"Pricing Rule Data"
+//	@Success		201				{object}	dto.PricingRule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/pricing-rules [post]
+func (ctrl *PricingController) CreateHandler(c echo.Context) error {
+	var input dto.PricingRule
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rule, err := ctrl.pricingService.Create(c.Request().Context(), &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// GetByIDHandler retrieves a pricing rule by ID
+//
+//	@Summary		Get a pricing rule by ID
+//	@Description	Retrieve a single pricing rule using its ID
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Param			ruleID			path		string	true	"Pricing Rule ID"
+//	@Success		200				{object}	dto.PricingRule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/pricing-rules/{ruleID} [get]
+func (ctrl *PricingController) GetByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	rule, err := ctrl.pricingService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// GetAllHandler retrieves all pricing rules
+//
+//	@Summary		List pricing rules
+//	@Description	Retrieve every pricing rule, disabled ones included, in creation order
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		200				{array}		dto.PricingRule
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/pricing-rules [get]
+func (ctrl *PricingController) GetAllHandler(c echo.Context) error {
+	rules, err := ctrl.pricingService.GetAll(c.Request().Context())
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// UpdateHandler updates a pricing rule by ID
+//
+//	@Summary		Update a pricing rule
+//	@Description	Replace a pricing rule. Existing transactions keep their breakdown until their amount changes.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string			true	"Admin token"
+//	@Param			ruleID			path		string			true	"Pricing Rule ID"
+//	@Param			rule			body		dto.PricingRule	true	"Pricing Rule Data"
+//	@Success		200				{object}	dto.PricingRule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/pricing-rules/{ruleID} [put]
+func (ctrl *PricingController) UpdateHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.PricingRule
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rule, err := ctrl.pricingService.Update(c.Request().Context(), id, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteHandler deletes a pricing rule by ID
+//
+//	@Summary		Delete a pricing rule
+//	@Description	Delete a pricing rule. Transactions keep the charges it computed.
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Param			ruleID			path	string	true	"Pricing Rule ID"
+//	@Success		204
+//	@Failure		400	{object}	map[string]string
+//	@Failure		401	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/pricing-rules/{ruleID} [delete]
+func (ctrl *PricingController) DeleteHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	if err = ctrl.pricingService.Delete(c.Request().Context(), id); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
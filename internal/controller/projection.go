@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ProjectionService interface {
+	Repair(ctx context.Context, id uuid.UUID) (bool, error)
+	ResyncAll(ctx context.Context) (int64, error)
+}
+
+type ProjectionController struct {
+	projectionService ProjectionService
+}
+
+func NewProjectionController(projectionService ProjectionService) *ProjectionController {
+	return &ProjectionController{
+		projectionService: projectionService,
+	}
+}
+
+// ResyncHandler queues every transaction for projection into Mongo
+//
+//	@Summary		Resync the Mongo read model
+//	@Description	Queue every Postgres transaction and Mongo document for projection, repairing drifted and orphaned documents
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		202				{object}	map[string]int64
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/projections/transactions [post]
+func (ctrl *ProjectionController) ResyncHandler(c echo.Context) error {
+	queued, err := ctrl.projectionService.ResyncAll(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]int64{"queued": queued})
+}
+
+// RepairHandler rewrites the Mongo document of a transaction
+//
+//	@Summary		Repair a Mongo document
+//	@Description	Rewrite the Mongo document of a transaction from Postgres, removing it if the transaction no longer exists
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Param			id				path	string	true	"Transaction ID"
+//	@Success		204
+//	@Failure		400	{object}	map[string]string
+//	@Failure		401	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/projections/transactions/{transactionID} [post]
+func (ctrl *ProjectionController) RepairHandler(c echo.Context) error {
+	idStr := c.Param("transactionID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	if _, err = ctrl.projectionService.Repair(c.Request().Context(), id); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type PurgeService interface {
+	Purge(ctx context.Context, filter dto.PurgeFilter) (*dto.PurgeResult, error)
+}
+
+type PurgeController struct {
+	purgeService PurgeService
+}
+
+func NewPurgeController(purgeService PurgeService) *PurgeController {
+	return &PurgeController{
+		purgeService: purgeService,
+	}
+}
+
+// PurgeHandler purges old deleted transactions
+//
+//	@Summary		Purge deleted transactions
+//	@Description	Permanently delete the transactions deleted longer ago than the retention period, with
+//	@Description	their line items, refunds, disputes, risk assessment and history, from Postgres and
+//	@Description	MongoDB. A transaction.purged event is published for each. Journal entries are kept.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Param			retention		query		string	false	"Retention as a Go duration, such as 720h"
+//	@Success		200				{object}	dto.PurgeResult
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/transactions/purge [post]
+func (ctrl *PurgeController) PurgeHandler(c echo.Context) error {
+	var filter dto.PurgeFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := ctrl.purgeService.Purge(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type RefundService interface {
+	Create(ctx context.Context, transactionID uuid.UUID, amount int64, currency, reason string) (*dto.Refund, error)
+	GetByID(ctx context.Context, transactionID, id uuid.UUID) (*dto.Refund, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]dto.Refund, error)
+	UpdateStatus(ctx context.Context, transactionID, id uuid.UUID, status string) (*dto.Refund, error)
+}
+
+type RefundController struct {
+	refundService RefundService
+}
+
+func NewRefundController(refundService RefundService) *RefundController {
+	return &RefundController{
+		refundService: refundService,
+	}
+}
+
+// CreateHandler requests a refund of a transaction
+//
+//	@Summary		Refund a transaction
+//	@Description	Request a full or partial refund of a completed transaction. The refund starts pending and,
+//	@Description	together with the other pending and succeeded refunds, may not exceed the transaction amount.
+//	@Tags			refunds
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string		true	"Transaction ID"
+//	@Param			refund			body		dto.Refund	true	"Refund Data"
+//	@Param			Idempotency-Key	header		string		false	"Replays the original response when retried with the same body"
+//	@Success		201				{object}	dto.Refund
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/refunds [post]
+func (ctrl *RefundController) CreateHandler(c echo.Context) error {
+	transactionID, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Refund
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	refundDTO, err := ctrl.refundService.Create(c.Request().Context(), transactionID, input.Amount, input.Currency, input.Reason)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, refundDTO)
+}
+
+// GetAllHandler lists the refunds of a transaction
+//
+//	@Summary		List the refunds of a transaction
+//	@Description	Retrieve every refund of a transaction, oldest first
+//	@Tags			refunds
+//	@Produce		json
+//	@Param			id	path		string	true	"Transaction ID"
+//	@Success		200	{array}		dto.Refund
+//	@Failure		400	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/refunds [get]
+func (ctrl *RefundController) GetAllHandler(c echo.Context) error {
+	transactionID, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	refunds, err := ctrl.refundService.GetByTransactionID(c.Request().Context(), transactionID)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, refunds)
+}
+
+// GetByIDHandler retrieves a refund by ID
+//
+//	@Summary		Get a refund by ID
+//	@Description	Retrieve a single refund of a transaction
+//	@Tags			refunds
+//	@Produce		json
+//	@Param			id			path		string	true	"Transaction ID"
+//	@Param			refundID	path		string	true	"Refund ID"
+//	@Success		200			{object}	dto.Refund
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/refunds/{refundID} [get]
+func (ctrl *RefundController) GetByIDHandler(c echo.Context) error {
+	transactionID, id, err := refundIDs(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	refundDTO, err := ctrl.refundService.GetByID(c.Request().Context(), transactionID, id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, refundDTO)
+}
+
+// UpdateHandler settles a refund
+//
+//	@Summary		Settle a refund
+//	@Description	Move a pending refund to succeeded or failed. A succeeded refund is added to the refunded
+//	@Description	amount of the transaction, which becomes partially_refunded or refunded.
+//	@Tags			refunds
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string		true	"Transaction ID"
+//	@Param			refundID	path		string		true	"Refund ID"
+//	@Param			refund		body		dto.Refund	true	"Refund with the new status"
+//	@Success		200			{object}	dto.Refund
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		422			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/refunds/{refundID} [put]
+func (ctrl *RefundController) UpdateHandler(c echo.Context) error {
+	transactionID, id, err := refundIDs(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Refund
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	refundDTO, err := ctrl.refundService.UpdateStatus(c.Request().Context(), transactionID, id, input.Status)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, refundDTO)
+}
+
+func refundIDs(c echo.Context) (transactionID, id uuid.UUID, err error) {
+	transactionID, err = uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	id, err = uuid.Parse(c.Param("refundID"))
+	return transactionID, id, err
+}
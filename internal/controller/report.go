@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type ReportService interface {
+	Totals(ctx context.Context, filter dto.TotalsFilter) (*dto.Totals, error)
+}
+
+type ReportController struct {
+	reportService ReportService
+}
+
+func NewReportController(reportService ReportService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+// TotalsHandler reports transaction totals in a currency
+//
+//	@Summary		Report transaction totals
+//	@Description	Sum the transaction amounts of every currency and convert the sums to the requested
+//	@Description	currency at the current exchange rates
+//	@Tags			reports
+//	@Produce		json
+//	@Param			currency		query		string	true	"ISO-4217 code to report in"
+//	@Param			status			query		string	false	"Status name"
+//	@Param			merchant_id		query		string	false	"Merchant ID"
+//	@Param			customer_id		query		string	false	"Customer ID"
+//	@Param			created_from	query		string	false	"Created at or after (RFC 3339)"
+//	@Param			created_to		query		string	false	"Created before (RFC 3339)"
+//	@Param			include_deleted	query		bool	false	"Include soft deleted transactions"
+//	@Success		200				{object}	dto.Totals
+//	@Failure		400				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/reports/totals [get]
+func (ctrl *ReportController) TotalsHandler(c echo.Context) error {
+	var filter dto.TotalsFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	totals, err := ctrl.reportService.Totals(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, totals)
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/requestctx"
+)
+
+const actorHeader = "X-Actor"
+
+// RequestContext stores the caller from the X-Actor header and the request ID in the request
+// context. It must run after the request ID middleware.
+func RequestContext(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		ctx = requestctx.WithActor(ctx, c.Request().Header.Get(actorHeader))
+		ctx = requestctx.WithRequestID(ctx, c.Response().Header().Get(echo.HeaderXRequestID))
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type ReviewService interface {
+	Approve(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+	Decline(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+}
+
+type ReviewController struct {
+	reviewService ReviewService
+}
+
+func NewReviewController(reviewService ReviewService) *ReviewController {
+	return &ReviewController{
+		reviewService: reviewService,
+	}
+}
+
+// ApproveHandler approves a transaction held for review
+//
+//	@Summary		Approve a reviewed transaction
+//	@Description	Release a transaction held for review by the risk screening back to created
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/transactions/{transactionID}/approve [post]
+func (ctrl *ReviewController) ApproveHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	transactionDTO, err := ctrl.reviewService.Approve(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transactionDTO)
+}
+
+// DeclineHandler declines a transaction held for review
+//
+//	@Summary		Decline a reviewed transaction
+//	@Description	Move a transaction held for review by the risk screening to declined
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/transactions/{transactionID}/decline [post]
+func (ctrl *ReviewController) DeclineHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	transactionDTO, err := ctrl.reviewService.Decline(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transactionDTO)
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type RiskService interface {
+	CreateRule(ctx context.Context, input *dto.RiskRule) (*dto.RiskRule, error)
+	GetRuleByID(ctx context.Context, id uuid.UUID) (*dto.RiskRule, error)
+	GetRules(ctx context.Context) ([]dto.RiskRule, error)
+	UpdateRule(ctx context.Context, id uuid.UUID, input *dto.RiskRule) (*dto.RiskRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	GetAssessment(ctx context.Context, transactionID uuid.UUID) (*dto.RiskAssessment, error)
+}
+
+type RiskController struct {
+	riskService RiskService
+}
+
+func NewRiskController(riskService RiskService) *RiskController {
+	return &RiskController{
+		riskService: riskService,
+	}
+}
+
+// CreateRuleHandler creates a new risk rule
+//
+//	@Summary		Create a risk rule
+//	@Description	Create a velocity, amount, blocklist or currency rule adding its score to the risk score of
+//	@Description	the new transactions it matches.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string			true	"Admin token"
+//	@Param			rule			body		dto.RiskRule	true	"Risk Rule Data"
+//	@Success		201				{object}	dto.RiskRule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/risk-rules [post]
+func (ctrl *RiskController) CreateRuleHandler(c echo.Context) error {
+	var input dto.RiskRule
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rule, err := ctrl.riskService.CreateRule(c.Request().Context(), &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// GetRuleByIDHandler retrieves a risk rule by ID
+//
+//	@Summary		Get a risk rule by ID
+//	@Description	Retrieve a single risk rule using its ID
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Param			ruleID			path		string	true	"Risk Rule ID"
+//	@Success		200				{object}	dto.RiskRule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/risk-rules/{ruleID} [get]
+func (ctrl *RiskController) GetRuleByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	rule, err := ctrl.riskService.GetRuleByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// GetRulesHandler retrieves all risk rules
+//
+//	@Summary		List risk rules
+//	@Description	Retrieve every risk rule, disabled ones included, in creation order
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		200				{array}		dto.RiskRule
+//	@Failure		401				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/risk-rules [get]
+func (ctrl *RiskController) GetRulesHandler(c echo.Context) error {
+	rules, err := ctrl.riskService.GetRules(c.Request().Context())
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// UpdateRuleHandler updates a risk rule by ID
+//
+//	@Summary		Update a risk rule
+//	@Description	Replace a risk rule. It applies to the transactions created afterwards.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string			true	"Admin token"
+//	@Param			ruleID			path		string			true	"Risk Rule ID"
+//	@Param			rule			body		dto.RiskRule	true	"Risk Rule Data"
+//	@Success		200				{object}	dto.RiskRule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/admin/risk-rules/{ruleID} [put]
+func (ctrl *RiskController) UpdateRuleHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.RiskRule
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	rule, err := ctrl.riskService.UpdateRule(c.Request().Context(), id, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRuleHandler deletes a risk rule by ID
+//
+//	@Summary		Delete a risk rule
+//	@Description	Delete a risk rule. Assessments keep the hits it produced.
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Param			ruleID			path	string	true	"Risk Rule ID"
+//	@Success		204
+//	@Failure		400	{object}	map[string]string
+//	@Failure		401	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/risk-rules/{ruleID} [delete]
+func (ctrl *RiskController) DeleteRuleHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("ruleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	if err = ctrl.riskService.DeleteRule(c.Request().Context(), id); err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetAssessmentHandler retrieves the risk assessment of a transaction
+//
+//	@Summary		Get the risk assessment of a transaction
+//	@Description	Retrieve the score, decision and matched rules of the screening of a transaction
+//	@Tags			transactions
+//	@Produce		json
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Success		200				{object}	dto.RiskAssessment
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/risk [get]
+func (ctrl *RiskController) GetAssessmentHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	assessment, err := ctrl.riskService.GetAssessment(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, assessment)
+}
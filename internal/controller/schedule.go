@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type ScheduleService interface {
+	Create(ctx context.Context, input *dto.Schedule) (*dto.Schedule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.Schedule, error)
+	GetAll(ctx context.Context, filter dto.ScheduleFilter) ([]dto.Schedule, error)
+	Pause(ctx context.Context, id uuid.UUID) (*dto.Schedule, error)
+	Resume(ctx context.Context, id uuid.UUID) (*dto.Schedule, error)
+	Cancel(ctx context.Context, id uuid.UUID) (*dto.Schedule, error)
+	Run(ctx context.Context) error
+}
+
+type ScheduleController struct {
+	scheduleService ScheduleService
+}
+
+func NewScheduleController(scheduleService ScheduleService) *ScheduleController {
+	return &ScheduleController{
+		scheduleService: scheduleService,
+	}
+}
+
+// CreateHandler creates a new schedule
+//
+//	@Summary		Create a schedule
+//	@Description	Create a recurring transaction. Every time the cron expression or interval comes due
+//	@Description	between start_at and end_at, a transaction is created from the schedule with its
+//	@Description	schedule_id and scheduled_at added to the metadata.
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Param			schedule		body		dto.Schedule	true	"Schedule Data"
+//	@Param			Idempotency-Key	header		string			false	"Replays the original response when retried with the same body"
+//	@Success		201				{object}	dto.Schedule
+//	@Failure		400				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/schedules [post]
+func (ctrl *ScheduleController) CreateHandler(c echo.Context) error {
+	var input dto.Schedule
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	schedule, err := ctrl.scheduleService.Create(c.Request().Context(), &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+// GetAllHandler lists schedules
+//
+//	@Summary		List schedules
+//	@Description	Retrieve the newest schedules
+//	@Tags			schedules
+//	@Produce		json
+//	@Param			merchant_id	query		string	false	"Merchant ID"
+//	@Param			customer_id	query		string	false	"Customer ID"
+//	@Param			status		query		string	false	"active, paused, cancelled or completed"
+//	@Param			limit		query		int		false	"Number of schedules, 50 by default and at most 200"
+//	@Success		200			{array}		dto.Schedule
+//	@Failure		400			{object}	map[string]string
+//	@Failure		422			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/schedules [get]
+func (ctrl *ScheduleController) GetAllHandler(c echo.Context) error {
+	var filter dto.ScheduleFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	schedules, err := ctrl.scheduleService.GetAll(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, schedules)
+}
+
+// GetByIDHandler retrieves a schedule by ID
+//
+//	@Summary		Get a schedule by ID
+//	@Description	Retrieve a single schedule using its ID
+//	@Tags			schedules
+//	@Produce		json
+//	@Param			scheduleID	path		string	true	"Schedule ID"
+//	@Success		200			{object}	dto.Schedule
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/schedules/{scheduleID} [get]
+func (ctrl *ScheduleController) GetByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("scheduleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	schedule, err := ctrl.scheduleService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// PauseHandler pauses a schedule
+//
+//	@Summary		Pause a schedule
+//	@Description	Stop an active schedule from creating transactions until it is resumed
+//	@Tags			schedules
+//	@Produce		json
+//	@Param			scheduleID	path		string	true	"Schedule ID"
+//	@Success		200			{object}	dto.Schedule
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/schedules/{scheduleID}/pause [post]
+func (ctrl *ScheduleController) PauseHandler(c echo.Context) error {
+	return ctrl.change(c, ctrl.scheduleService.Pause)
+}
+
+// ResumeHandler resumes a schedule
+//
+//	@Summary		Resume a schedule
+//	@Description	Reactivate a paused schedule. Runs missed while it was paused are skipped.
+//	@Tags			schedules
+//	@Produce		json
+//	@Param			scheduleID	path		string	true	"Schedule ID"
+//	@Success		200			{object}	dto.Schedule
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/schedules/{scheduleID}/resume [post]
+func (ctrl *ScheduleController) ResumeHandler(c echo.Context) error {
+	return ctrl.change(c, ctrl.scheduleService.Resume)
+}
+
+// CancelHandler cancels a schedule
+//
+//	@Summary		Cancel a schedule
+//	@Description	Stop an active or paused schedule for good
+//	@Tags			schedules
+//	@Produce		json
+//	@Param			scheduleID	path		string	true	"Schedule ID"
+//	@Success		200			{object}	dto.Schedule
+//	@Failure		400			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/schedules/{scheduleID}/cancel [post]
+func (ctrl *ScheduleController) CancelHandler(c echo.Context) error {
+	return ctrl.change(c, ctrl.scheduleService.Cancel)
+}
+
+// RunHandler runs the due schedules
+//
+//	@Summary		Run the due schedules
+//	@Description	Create the transactions of the schedules that came due, as the scheduler does
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/schedules/run [post]
+func (ctrl *ScheduleController) RunHandler(c echo.Context) error {
+	if err := ctrl.scheduleService.Run(c.Request().Context()); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// change applies a status change of the schedule in the path.
+func (ctrl *ScheduleController) change(
+	c echo.Context, fn func(ctx context.Context, id uuid.UUID) (*dto.Schedule, error)) error {
+	id, err := uuid.Parse(c.Param("scheduleID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	schedule, err := fn(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+type SettlementService interface {
+	Settle(ctx context.Context) error
+	GetAll(ctx context.Context, filter dto.SettlementBatchFilter) ([]dto.SettlementBatch, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.SettlementBatch, error)
+	GetTransactions(ctx context.Context, id uuid.UUID) ([]dto.Transaction, error)
+	Export(ctx context.Context, id uuid.UUID) ([]byte, error)
+}
+
+type SettlementController struct {
+	settlementService SettlementService
+}
+
+func NewSettlementController(settlementService SettlementService) *SettlementController {
+	return &SettlementController{
+		settlementService: settlementService,
+	}
+}
+
+// GetAllHandler lists settlement batches
+//
+//	@Summary		List settlement batches
+//	@Description	Retrieve the newest settlement batches. Open batches report their running totals.
+//	@Tags			settlements
+//	@Produce		json
+//	@Param			merchant_id	query		string	false	"Merchant ID"
+//	@Param			status		query		string	false	"open or closed"
+//	@Param			from		query		string	false	"Batch date on or after (YYYY-MM-DD)"
+//	@Param			to			query		string	false	"Batch date on or before (YYYY-MM-DD)"
+//	@Param			limit		query		int		false	"Number of batches, 50 by default and at most 200"
+//	@Success		200			{array}		dto.SettlementBatch
+//	@Failure		400			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/settlement-batches [get]
+func (ctrl *SettlementController) GetAllHandler(c echo.Context) error {
+	var filter dto.SettlementBatchFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	batches, err := ctrl.settlementService.GetAll(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, batches)
+}
+
+// GetByIDHandler retrieves a settlement batch by ID
+//
+//	@Summary		Get a settlement batch by ID
+//	@Description	Retrieve a single settlement batch with its gross, fees, tax, refunds, chargebacks and net totals
+//	@Tags			settlements
+//	@Produce		json
+//	@Param			batchID	path		string	true	"Settlement Batch ID"
+//	@Success		200		{object}	dto.SettlementBatch
+//	@Failure		400		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/v1/settlement-batches/{batchID} [get]
+func (ctrl *SettlementController) GetByIDHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("batchID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	batch, err := ctrl.settlementService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, batch)
+}
+
+// GetTransactionsHandler lists the transactions of a settlement batch
+//
+//	@Summary		List the transactions of a settlement batch
+//	@Description	Retrieve the transactions of a settlement batch, oldest first, as JSON or, with format=csv,
+//	@Description	as a CSV export with the gross, fees, tax, refunds, chargebacks and net of each transaction
+//	@Description	and adjustment, as settled once the batch is closed.
+//	@Tags			settlements
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			batchID	path		string	true	"Settlement Batch ID"
+//	@Param			format	query		string	false	"json (default) or csv"
+//	@Success		200		{array}		dto.Transaction
+//	@Failure		400		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/v1/settlement-batches/{batchID}/transactions [get]
+func (ctrl *SettlementController) GetTransactionsHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("batchID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	switch c.QueryParam("format") {
+	case "", "json":
+	case "csv":
+		data, err := ctrl.settlementService.Export(c.Request().Context(), id)
+		if err != nil {
+			return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="settlement-`+id.String()+`.csv"`)
+		return c.Blob(http.StatusOK, "text/csv", data)
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be json or csv"})
+	}
+
+	transactions, err := ctrl.settlementService.GetTransactions(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transactions)
+}
+
+// SettleHandler runs the settlement job
+//
+//	@Summary		Run the settlement job
+//	@Description	Add unbatched collected transactions to the open batches of today and close the batches
+//	@Description	of earlier days, as the scheduled job does
+//	@Tags			admin
+//	@Param			X-Admin-Token	header	string	true	"Admin token"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/v1/admin/settlement-batches/settle [post]
+func (ctrl *SettlementController) SettleHandler(c echo.Context) error {
+	if err := ctrl.settlementService.Settle(c.Request().Context()); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
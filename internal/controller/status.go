@@ -9,7 +9,7 @@ import (
 )
 
 type StatusService interface {
-	Create(name string) (*dto.Status, error)
+	Create(name string, transitions []string) (*dto.Status, error)
 	GetByID(id uuid.UUID) (*dto.Status, error)
 	GetAll() ([]dto.Status, error)
 }
@@ -28,13 +28,14 @@ func NewStatusController(
 // CreateHandler creates a new status
 //
 //	@Summary		Create a status
-//	@Description	Create a new status with a name
+//	@Description	Create a new status with a name and the statuses it may transition to
 //	@Tags			statuses
 //	@Accept			json
 //	@Produce		json
 //	@Param			status	body		dto.Status	true	"Status Data"
 //	@Success		201		{object}	dto.Status
 //	@Failure		400		{object}	map[string]string
+//	@Failure		422		{object}	map[string]string
 //	@Failure		500		{object}	map[string]string
 //	@Router			/v1/statuses [post]
 func (ctrl *StatusController) CreateHandler(c echo.Context) error {
@@ -43,9 +44,9 @@ func (ctrl *StatusController) CreateHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	status, err := ctrl.statusService.Create(input.Name)
+	status, err := ctrl.statusService.Create(input.Name, input.Transitions)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusCreated, status)
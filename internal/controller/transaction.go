@@ -1,51 +1,64 @@
 package controller
 
 import (
-	"fmt"
+	"context"
+	"io"
+	"mime"
 	"net/http"
-	_ "time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/service"
 )
 
-type TransactionService interface {
-	Create(value float64) (*dto.Transaction, error)
-	GetByID(id uuid.UUID) (*dto.Transaction, error)
-	GetAll() ([]dto.Transaction, error)
-	Update(id uuid.UUID, status string, value float64) (*dto.Transaction, error)
-	Delete(id uuid.UUID) (*dto.Transaction, error)
+// patchFormats maps the media types of patch bodies to their patch format.
+var patchFormats = map[string]string{
+	"application/merge-patch+json": service.MergePatch,
+	"application/json":             service.MergePatch,
+	"application/json-patch+json":  service.JSONPatch,
 }
 
-type NotificationService interface {
-	Publish(message any) error
+type TransactionService interface {
+	Create(ctx context.Context, input *dto.Transaction) (*dto.Transaction, error)
+	Quote(ctx context.Context, input *dto.Transaction) (*dto.Breakdown, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+	GetAll(ctx context.Context, filter dto.TransactionFilter) (*dto.TransactionPage, error)
+	Update(ctx context.Context, id uuid.UUID, input *dto.Transaction) (*dto.Transaction, error)
+	Patch(ctx context.Context, id uuid.UUID, format string, patch []byte) (*dto.Transaction, error)
+	Delete(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+	Restore(ctx context.Context, id uuid.UUID) (*dto.Transaction, error)
+	Split(ctx context.Context, id uuid.UUID, input *dto.Split) ([]dto.Transaction, error)
+	GetChildren(ctx context.Context, id uuid.UUID) ([]dto.Transaction, error)
 }
 
 type TransactionController struct {
-	transactionService  TransactionService
-	notificationService NotificationService
+	transactionService TransactionService
 }
 
 func NewTransactionController(
-	transactionService TransactionService, notificationService NotificationService) *TransactionController {
+	transactionService TransactionService) *TransactionController {
 	return &TransactionController{
-		transactionService:  transactionService,
-		notificationService: notificationService,
+		transactionService: transactionService,
 	}
 }
 
 // CreateHandler creates a new transaction
 //
 //	@Summary		Create a transaction
-//	@Description	Create a new transaction with a value
+//	@Description	Create a new transaction with an amount in minor units and an ISO-4217 currency, optionally
+//	@Description	paid by a customer to a merchant. With line_items, the amount is computed from them when
+//	@Description	zero and must match their total otherwise.
 //	@Tags			transactions
 //	@Accept			json
 //	@Produce		json
-//	@Param			transaction	body		dto.Transaction	true	"Transaction Data"
-//	@Success		201			{object}	dto.Transaction
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
+//	@Param			transaction		body		dto.Transaction	true	"Transaction Data"
+//	@Param			Idempotency-Key	header		string			false	"Replays the original response when retried with the same body"
+//	@Success		201				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
 //	@Router			/v1/transactions [post]
 func (ctrl *TransactionController) CreateHandler(c echo.Context) error {
 	var input dto.Transaction
@@ -53,17 +66,39 @@ func (ctrl *TransactionController) CreateHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	transactionDTO, err := ctrl.transactionService.Create(input.Value)
+	transactionDTO, err := ctrl.transactionService.Create(c.Request().Context(), &input)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
-	err = ctrl.notificationService.Publish(transactionDTO)
+	return c.JSON(http.StatusCreated, transactionDTO)
+}
+
+// QuoteHandler prices a transaction without creating it
+//
+//	@Summary		Quote a transaction
+//	@Description	Return the fees, tax and net amount a transaction would be charged, without creating it
+//	@Tags			transactions
+//	@Accept			json
+//	@Produce		json
+//	@Param			transaction	body		dto.Transaction	true	"Transaction Data"
+//	@Success		200			{object}	dto.Breakdown
+//	@Failure		400			{object}	map[string]string
+//	@Failure		422			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/v1/transactions/quote [post]
+func (ctrl *TransactionController) QuoteHandler(c echo.Context) error {
+	var input dto.Transaction
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	breakdown, err := ctrl.transactionService.Quote(c.Request().Context(), &input)
 	if err != nil {
-		return c.JSON(http.StatusAccepted, transactionDTO)
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, transactionDTO)
+	return c.JSON(http.StatusOK, breakdown)
 }
 
 // GetByIDHandler retrieves a transaction by ID
@@ -84,7 +119,7 @@ func (ctrl *TransactionController) GetByIDHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
 	}
 
-	transactionDTO, err := ctrl.transactionService.GetByID(id)
+	transactionDTO, err := ctrl.transactionService.GetByID(c.Request().Context(), id)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
@@ -92,36 +127,64 @@ func (ctrl *TransactionController) GetByIDHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, transactionDTO)
 }
 
-// GetAllHandler retrieves all transactions
+// GetAllHandler retrieves a page of transactions
 //
-//	@Summary		Get all transactions
-//	@Description	Retrieve all transactions
+//	@Summary		List transactions
+//	@Description	Retrieve a page of transactions matching the filters, following next_cursor for the next page
 //	@Tags			transactions
 //	@Produce		json
-//	@Success		200	{array}		dto.Transaction
-//	@Failure		500	{object}	map[string]string
+//	@Param			cursor			query		string		false	"next_cursor of the previous page"
+//	@Param			limit			query		int			false	"Page size, 50 by default and at most 200"
+//	@Param			sort			query		string		false	"created_at, updated_at or amount, prefixed with - for descending order (default -created_at)"
+//	@Param			status			query		string		false	"Status name"
+//	@Param			merchant_id		query		string		false	"Merchant ID"
+//	@Param			customer_id		query		string		false	"Customer ID"
+//	@Param			currency		query		string		false	"ISO-4217 currency"
+//	@Param			min_amount		query		int			false	"Minimum amount in minor units"
+//	@Param			max_amount		query		int			false	"Maximum amount in minor units"
+//	@Param			created_from	query		string		false	"Created at or after (RFC 3339)"
+//	@Param			created_to		query		string		false	"Created before (RFC 3339)"
+//	@Param			updated_from	query		string		false	"Updated at or after (RFC 3339)"
+//	@Param			updated_to		query		string		false	"Updated before (RFC 3339)"
+//	@Param			include_deleted	query		bool		false	"Include soft deleted transactions"
+//	@Param			metadata		query		[]string	false	"key:value metadata pairs that must all match"	collectionFormat(multi)
+//	@Param			tag				query		[]string	false	"Tags that must all be present"					collectionFormat(multi)
+//	@Success		200				{object}	dto.TransactionPage
+//	@Failure		400				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
 //	@Router			/v1/transactions [get]
 func (ctrl *TransactionController) GetAllHandler(c echo.Context) error {
-	transactionsDTOs, err := ctrl.transactionService.GetAll()
+	var filter dto.TransactionFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	page, err := ctrl.transactionService.GetAll(c.Request().Context(), filter)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, transactionsDTOs)
+	return c.JSON(http.StatusOK, page)
 }
 
 // UpdateHandler updates a transaction by ID
 //
 //	@Summary		Update a transaction
-//	@Description	Update a transaction's status and value by its ID
+//	@Description	Update a transaction's status and amount by its ID. The status must be reachable from the current one.
+//	@Description	Metadata and tags are replaced when present and kept otherwise.
 //	@Tags			transactions
 //	@Accept			json
 //	@Produce		json
-//	@Param			id			path		string			true	"Transaction ID"
-//	@Param			transaction	body		dto.Transaction	true	"Transaction Data"
-//	@Success		200			{object}	dto.Transaction
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
+//	@Param			id				path		string			true	"Transaction ID"
+//	@Param			transaction		body		dto.Transaction	true	"Transaction Data"
+//	@Param			Idempotency-Key	header		string			false	"Replays the original response when retried with the same body"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
 //	@Router			/v1/transactions/{transactionID} [put]
 func (ctrl *TransactionController) UpdateHandler(c echo.Context) error {
 	idStr := c.Param("transactionID")
@@ -135,18 +198,68 @@ func (ctrl *TransactionController) UpdateHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	updatedTransactionDTO, err := ctrl.transactionService.Update(id, input.Status, input.Value)
+	updatedTransactionDTO, err := ctrl.transactionService.Update(c.Request().Context(), id, &input)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
-	err = ctrl.notificationService.Publish(updatedTransactionDTO)
+	return c.JSON(http.StatusOK, updatedTransactionDTO)
+}
+
+// PatchHandler patches a transaction by ID
+//
+//	@Summary		Patch a transaction
+//	@Description	Change individual fields of a transaction with an RFC 7396 merge patch (application/merge-patch+json
+//	@Description	or application/json) or an RFC 6902 JSON Patch (application/json-patch+json). Only status, amount,
+//	@Description	metadata and tags can change, and removing metadata or tags clears them. A failed JSON Patch test
+//	@Description	returns 409.
+//	@Tags			transactions
+//	@Accept			json,application/merge-patch+json,application/json-patch+json
+//	@Produce		json
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Param			patch			body		object	true	"Merge patch or JSON Patch"
+//	@Param			Idempotency-Key	header		string	false	"Replays the original response when retried with the same body"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		415				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID} [patch]
+func (ctrl *TransactionController) PatchHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
 	if err != nil {
-		fmt.Print(err.Error())
-		return c.JSON(http.StatusAccepted, updatedTransactionDTO)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
 	}
 
-	return c.JSON(http.StatusOK, updatedTransactionDTO)
+	format, ok := patchFormats[mediaType(c.Request().Header.Get(echo.HeaderContentType))]
+	if !ok {
+		return c.JSON(http.StatusUnsupportedMediaType,
+			map[string]string{"error": "Content-Type must be application/merge-patch+json or application/json-patch+json"})
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	transaction, err := ctrl.transactionService.Patch(c.Request().Context(), id, format, patch)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transaction)
+}
+
+// mediaType returns the media type of a Content-Type header without its parameters.
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
 }
 
 // DeleteHandler deletes a transaction by ID
@@ -158,6 +271,8 @@ func (ctrl *TransactionController) UpdateHandler(c echo.Context) error {
 //	@Param			id	path	string	true	"Transaction ID"
 //	@Success		204
 //	@Failure		400	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		409	{object}	map[string]string
 //	@Failure		500	{object}	map[string]string
 //	@Router			/v1/transactions/{transactionID} [delete]
 func (ctrl *TransactionController) DeleteHandler(c echo.Context) error {
@@ -167,15 +282,103 @@ func (ctrl *TransactionController) DeleteHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
 	}
 
-	transactionDTO, err := ctrl.transactionService.Delete(id)
+	_, err = ctrl.transactionService.Delete(c.Request().Context(), id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
 	}
 
-	err = ctrl.notificationService.Publish(transactionDTO)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreHandler restores a deleted transaction
+//
+//	@Summary		Restore a deleted transaction
+//	@Description	Undo the soft deletion of a transaction, moving it back to the status it had when it was
+//	@Description	deleted
+//	@Tags			transactions
+//	@Produce		json
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Param			Idempotency-Key	header		string	false	"Replays the original response when retried with the same body"
+//	@Success		200				{object}	dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/restore [post]
+func (ctrl *TransactionController) RestoreHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
 	if err != nil {
-		return c.JSON(http.StatusAccepted, transactionDTO)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
 	}
 
-	return c.NoContent(http.StatusNoContent)
+	transaction, err := ctrl.transactionService.Restore(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, transaction)
+}
+
+// SplitHandler splits a transaction into installments or merchant shares
+//
+//	@Summary		Split a transaction
+//	@Description	Split a created transaction into count equal monthly installments or into explicit parts,
+//	@Description	installments with a due_at or merchant shares with a merchant_id, summing to its amount.
+//	@Description	The parts become child transactions and the status of the parent follows theirs.
+//	@Tags			transactions
+//	@Accept			json
+//	@Produce		json
+//	@Param			transactionID	path		string		true	"Transaction ID"
+//	@Param			split			body		dto.Split	true	"Split Data"
+//	@Param			Idempotency-Key	header		string		false	"Replays the original response when retried with the same body"
+//	@Success		201				{array}		dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		422				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/split [post]
+func (ctrl *TransactionController) SplitHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	var input dto.Split
+	if err = c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	children, err := ctrl.transactionService.Split(c.Request().Context(), id, &input)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, children)
+}
+
+// GetChildrenHandler lists the children of a split transaction
+//
+//	@Summary		List the children of a split transaction
+//	@Description	Retrieve the installments or merchant shares of a split transaction, deleted ones included
+//	@Tags			transactions
+//	@Produce		json
+//	@Param			transactionID	path		string	true	"Transaction ID"
+//	@Success		200				{array}		dto.Transaction
+//	@Failure		400				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/v1/transactions/{transactionID}/children [get]
+func (ctrl *TransactionController) GetChildrenHandler(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("transactionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID format"})
+	}
+
+	children, err := ctrl.transactionService.GetChildren(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(errorStatus(err, http.StatusInternalServerError), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, children)
 }
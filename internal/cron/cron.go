@@ -0,0 +1,162 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// searchYears bounds the search for the next run of expressions that rarely or never fire, such as
+// 0 0 30 2 *.
+const searchYears = 5
+
+// field is the range of one of the five fields of an expression.
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Expression is a parsed five field cron expression: minute, hour, day of month, month and day of
+// week, each a *, a value, a range or a comma separated list of them with an optional /step. Day of
+// week 0 and 7 are Sunday. Like cron, a day matches when either day field does if neither
+// starts with *.
+type Expression struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	anyDayOfMonth, anyDayOfWeek                bool
+}
+
+// Parse parses a five field cron expression.
+func Parse(expr string) (*Expression, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("%w: %q must have %d fields", ErrInvalidExpression, expr, len(fields))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	// Sunday is both 0 and 7.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Expression{
+		minute:        sets[0],
+		hour:          sets[1],
+		dayOfMonth:    sets[2],
+		month:         sets[3],
+		dayOfWeek:     sets[4],
+		anyDayOfMonth: strings.HasPrefix(parts[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// Next returns the first minute strictly after t that e matches in UTC. It returns the zero time
+// when e does not match in the next few years.
+func (e *Expression) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(searchYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !has(e.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !e.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !has(e.hour, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !has(e.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (e *Expression) matchesDay(t time.Time) bool {
+	dayOfMonth := has(e.dayOfMonth, t.Day())
+	dayOfWeek := has(e.dayOfWeek, int(t.Weekday()))
+	if e.anyDayOfMonth || e.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<value) != 0
+}
+
+// parseField returns the set of values of f matched by a comma separated list of *, values and
+// ranges, each with an optional /step.
+func parseField(part string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(part, ",") {
+		base, step, hasStep := strings.Cut(item, "/")
+		increment := 1
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: step %q of the %s field", ErrInvalidExpression, step, f.name)
+			}
+			increment = n
+		}
+
+		low, high := f.min, f.max
+		if base != "*" {
+			var err error
+			if low, high, err = parseRange(base, f); err != nil {
+				return 0, err
+			}
+			if hasStep && !strings.Contains(base, "-") {
+				high = f.max
+			}
+		}
+
+		for value := low; value <= high; value += increment {
+			set |= 1 << value
+		}
+	}
+
+	return set, nil
+}
+
+func parseRange(base string, f field) (int, int, error) {
+	lowText, highText, isRange := strings.Cut(base, "-")
+	low, err := strconv.Atoi(lowText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q in the %s field", ErrInvalidExpression, base, f.name)
+	}
+	high := low
+	if isRange {
+		if high, err = strconv.Atoi(highText); err != nil {
+			return 0, 0, fmt.Errorf("%w: %q in the %s field", ErrInvalidExpression, base, f.name)
+		}
+	}
+
+	if low < f.min || high > f.max || low > high {
+		return 0, 0, fmt.Errorf("%w: %q is outside %d-%d in the %s field", ErrInvalidExpression, base, f.min, f.max, f.name)
+	}
+
+	return low, high, nil
+}
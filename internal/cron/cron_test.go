@@ -0,0 +1,119 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseRejects(t *testing.T) {
+	tests := []string{
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"-1 * * * *",
+		"*/0 * * * *",
+		"*/-5 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"1,,2 * * * *",
+		"1-2-3 * * * *",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); !errors.Is(err, ErrInvalidExpression) {
+				t.Fatalf("Parse(%q) error = %v, want %v", expr, err, ErrInvalidExpression)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", utc("2024-01-01T00:00:30"), utc("2024-01-01T00:01:00")},
+		{"strictly after a match", "0 0 * * *", utc("2024-01-01T00:00:00"), utc("2024-01-02T00:00:00")},
+		{"step", "*/15 * * * *", utc("2024-01-01T00:07:30"), utc("2024-01-01T00:15:00")},
+		{"step from a value", "5/20 * * * *", utc("2024-01-01T00:30:00"), utc("2024-01-01T00:45:00")},
+		{"step within a range", "0-30/10 * * * *", utc("2024-01-01T00:31:00"), utc("2024-01-01T01:00:00")},
+		{"lists and ranges", "5,10-12 8-9 * * 1-5", utc("2024-09-06T09:12:00"), utc("2024-09-09T08:05:00")},
+		{"next month", "0 9 1 * *", utc("2024-01-31T10:00:00"), utc("2024-02-01T09:00:00")},
+		{"skips short months", "30 23 31 * *", utc("2024-04-01T00:00:00"), utc("2024-05-31T23:30:00")},
+		{"next year", "0 0 1 1 *", utc("2024-12-31T23:59:00"), utc("2025-01-01T00:00:00")},
+		{"leap day", "0 0 29 2 *", utc("2023-03-01T00:00:00"), utc("2024-02-29T00:00:00")},
+		{"sunday as 0", "0 0 * * 0", utc("2024-09-02T00:00:00"), utc("2024-09-08T00:00:00")},
+		{"sunday as 7", "0 0 * * 7", utc("2024-09-02T00:00:00"), utc("2024-09-08T00:00:00")},
+		// 2024-09-01 is a Sunday. With both day fields restricted a day matches either of them.
+		{"day of month or week", "0 12 13 * 5", utc("2024-09-01T00:00:00"), utc("2024-09-06T12:00:00")},
+		{"day of month or week, month first", "0 12 2 * 5", utc("2024-09-01T00:00:00"), utc("2024-09-02T12:00:00")},
+		// A day field starting with * makes both of them apply.
+		{"any day of month and week", "0 12 * * 5", utc("2024-09-01T00:00:00"), utc("2024-09-06T12:00:00")},
+		{"stepped day of month and week", "0 12 */10 * 1", utc("2024-09-01T00:00:00"), utc("2024-10-21T12:00:00")},
+		// Expressions are evaluated in UTC, so daylight saving time changes neither skip nor repeat
+		// runs. 2024-03-10 02:00 does not exist in New York and 2024-11-03 01:00 happens twice.
+		{"spring forward", "0 2 * * *", time.Date(2024, 3, 10, 1, 30, 0, 0, newYork), utc("2024-03-11T02:00:00")},
+		{"fall back", "0 6 * * *", time.Date(2024, 11, 3, 1, 30, 0, 0, newYork), utc("2024-11-03T06:00:00")},
+		{"never in the search window", "0 0 30 2 *", utc("2024-01-01T00:00:00"), time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expression, err := Parse(test.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", test.expr, err)
+			}
+			if got := expression.Next(test.after); !got.Equal(test.want) {
+				t.Fatalf("Next(%s) = %s, want %s", test.after, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNextSequence(t *testing.T) {
+	expression, err := Parse("0 9 * * 1,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Time{
+		utc("2024-02-26T09:00:00"),
+		utc("2024-02-28T09:00:00"),
+		utc("2024-03-04T09:00:00"),
+		utc("2024-03-06T09:00:00"),
+	}
+	after := utc("2024-02-25T00:00:00")
+	for _, run := range want {
+		after = expression.Next(after)
+		if !after.Equal(run) {
+			t.Fatalf("Next() = %s, want %s", after, run)
+		}
+	}
+}
+
+func utc(value string) time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05", value)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
@@ -1,14 +1,41 @@
 package database
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
 	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
+// statusGraph is the default transaction status graph. Statuses without outgoing transitions are terminal.
+var statusGraph = []struct {
+	name string
+	next []string
+}{
+	{"created", []string{"pending", "authorized", "completed", "review", "declined", "split", "expired", "deleted"}},
+	{"review", []string{"created", "declined", "deleted"}},
+	{"declined", []string{"deleted"}},
+	{"split", []string{"partially_paid", "completed", "voided", "deleted"}},
+	{"partially_paid", []string{"completed", "deleted"}},
+	{"pending", []string{"authorized", "completed", "expired", "deleted"}},
+	{"expired", []string{"deleted"}},
+	{"authorized", []string{"partially_captured", "completed", "voided", "deleted"}},
+	{"partially_captured", []string{"completed", "deleted"}},
+	{"voided", []string{"deleted"}},
+	{"completed", []string{"partially_refunded", "refunded", "deleted"}},
+	{"partially_refunded", []string{"refunded", "deleted"}},
+	{"refunded", []string{"deleted"}},
+	{"deleted", nil},
+}
+
 type Mongo struct {
 	Collection *mongo.Collection
 }
@@ -17,16 +44,20 @@ type Postgres struct {
 	DB *gorm.DB
 }
 
-func NewMongo(uri, databaseName, collectionName string) Mongo {
+func NewMongo(uri, databaseName, collectionName, legacyCurrency string) Mongo {
 	client, _ := mongo.Connect(options.Client().ApplyURI(uri))
 	collection := client.Database(databaseName).Collection(collectionName)
 
+	if err := migrateMongoLegacyValues(collection, legacyCurrency); err != nil {
+		panic(err)
+	}
+
 	return Mongo{
 		collection,
 	}
 }
 
-func NewPostgres(dsn, schemaName string) Postgres {
+func NewPostgres(dsn, schemaName, legacyCurrency string) Postgres {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		NamingStrategy: schema.NamingStrategy{
 			TablePrefix:   schemaName,
@@ -38,19 +69,51 @@ func NewPostgres(dsn, schemaName string) Postgres {
 
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
 
-	err = db.AutoMigrate(&entity.Status{}, &entity.Transaction{})
+	err = db.AutoMigrate(
+		&entity.Status{},
+		&entity.Merchant{},
+		&entity.Customer{},
+		&entity.Transaction{},
+		&entity.LineItem{},
+		&entity.IdempotencyKey{},
+		&entity.OutboxEvent{},
+		&entity.ProjectionTask{},
+		&entity.TransactionEvent{},
+		&entity.Refund{},
+		&entity.Dispute{},
+		&entity.LedgerAccount{},
+		&entity.JournalEntry{},
+		&entity.Posting{},
+		&entity.PricingRule{},
+		&entity.RiskRule{},
+		&entity.RiskAssessment{},
+		&entity.Limit{},
+		&entity.LimitUsage{},
+		&entity.SettlementBatch{},
+		&entity.SettlementEntry{},
+		&entity.Schedule{},
+		&entity.JobRun{},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = migratePostgresLegacyValues(db, legacyCurrency)
+	if err != nil {
+		panic(err)
+	}
+
+	err = backfillNetAmounts(db)
+	if err != nil {
+		panic(err)
+	}
+
+	err = seedStatuses(db)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Exec(`
-        INSERT INTO transactions.statuses (id, name) VALUES 
-        (uuid_generate_v4(), 'created'), 
-        (uuid_generate_v4(), 'pending'), 
-        (uuid_generate_v4(), 'completed'), 
-        (uuid_generate_v4(), 'deleted')
-        ON CONFLICT DO NOTHING;
-    `).Error
+	err = protectHistory(db, schemaName)
 	if err != nil {
 		panic(err)
 	}
@@ -59,3 +122,158 @@ func NewPostgres(dsn, schemaName string) Postgres {
 		db,
 	}
 }
+
+// seedStatuses inserts the statuses and transitions of statusGraph, keeping any that already exist.
+func seedStatuses(db *gorm.DB) error {
+	statusesTable, err := tableName(db, &entity.Status{})
+	if err != nil {
+		return err
+	}
+
+	transitionsTable, err := joinTableName(db, &entity.Status{}, "Next")
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, status := range statusGraph {
+			err := tx.Exec(`INSERT INTO ? (id, name) VALUES (uuid_generate_v4(), ?) ON CONFLICT DO NOTHING`,
+				clause.Table{Name: statusesTable}, status.name).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, status := range statusGraph {
+			for _, next := range status.next {
+				err := tx.Exec(`INSERT INTO ? (from_status_id, to_status_id)
+					SELECT f.id, t.id FROM ? f, ? t WHERE f.name = ? AND t.name = ?
+					ON CONFLICT DO NOTHING`,
+					clause.Table{Name: transitionsTable}, clause.Table{Name: statusesTable}, clause.Table{Name: statusesTable},
+					status.name, next).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// backfillNetAmounts sets the net amount of transactions priced before fees and taxes existed,
+// which is a no-op for the others.
+func backfillNetAmounts(db *gorm.DB) error {
+	return db.Unscoped().Model(&entity.Transaction{}).
+		Where("net_amount = 0 AND amount <> fee_amount + tax_amount").
+		UpdateColumn("net_amount", gorm.Expr("amount - fee_amount - tax_amount")).Error
+}
+
+// protectHistory makes transaction_events append-only by rejecting updates and deletes, except the
+// deletes of a purge, which sets transactions.purge for its transaction. The trigger function is
+// created in the schema of the tables.
+func protectHistory(db *gorm.DB, schemaName string) error {
+	eventsTable, err := tableName(db, &entity.TransactionEvent{})
+	if err != nil {
+		return err
+	}
+
+	function := clause.Table{Name: schemaName + "reject_history_change"}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`CREATE OR REPLACE FUNCTION ?() RETURNS trigger AS $$
+			BEGIN
+				IF TG_OP = 'DELETE' AND current_setting('transactions.purge', true) = 'on' THEN
+					RETURN OLD;
+				END IF;
+				RAISE EXCEPTION 'transaction_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql`, function).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`DROP TRIGGER IF EXISTS transaction_events_append_only ON ?`, clause.Table{Name: eventsTable}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`CREATE TRIGGER transaction_events_append_only
+			BEFORE UPDATE OR DELETE ON ?
+			FOR EACH ROW EXECUTE FUNCTION ?()`, clause.Table{Name: eventsTable}, function).Error
+	})
+}
+
+// tableName resolves the schema qualified table name of model for raw SQL. Statement.Table drops
+// the schema, so the name is taken from the parsed schema.
+func tableName(db *gorm.DB, model any) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+
+	return stmt.Schema.Table, nil
+}
+
+// joinTableName resolves the schema qualified name of the join table behind the many2many field of model.
+func joinTableName(db *gorm.DB, model any, field string) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+
+	relationship, ok := stmt.Schema.Relationships.Relations[field]
+	if !ok || relationship.JoinTable == nil {
+		return "", fmt.Errorf("%s.%s has no join table", stmt.Schema.Name, field)
+	}
+
+	return relationship.JoinTable.Table, nil
+}
+
+// migratePostgresLegacyValues converts the old floating point value column into minor units of
+// legacyCurrency and drops it, so it is a no-op once every row has been migrated.
+func migratePostgresLegacyValues(db *gorm.DB, legacyCurrency string) error {
+	if !db.Migrator().HasColumn(&entity.Transaction{}, "value") {
+		return nil
+	}
+
+	factor, err := currency.Factor(legacyCurrency)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&entity.Transaction{}).
+			Where("value IS NOT NULL").
+			UpdateColumns(map[string]any{
+				"amount":   gorm.Expr("ROUND(value * ?)::bigint", factor),
+				"currency": currency.Normalize(legacyCurrency),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropColumn(&entity.Transaction{}, "value")
+	})
+}
+
+// migrateMongoLegacyValues applies the same conversion as migratePostgresLegacyValues to documents
+// that still carry a value field.
+func migrateMongoLegacyValues(collection *mongo.Collection, legacyCurrency string) error {
+	factor, err := currency.Factor(legacyCurrency)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"value": bson.M{"$exists": true}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{"$value", factor}}, 0}}},
+			"currency": currency.Normalize(legacyCurrency),
+		}}},
+		{{Key: "$unset", Value: "value"}},
+	}
+
+	_, err = collection.UpdateMany(context.TODO(), filter, update)
+	return err
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transaction runs fn inside a database transaction carried by the context passed to fn.
+// Repositories reach it through Conn. Nested calls join the outer transaction.
+func (p Postgres) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// WithAdvisoryLock runs fn in a transaction holding the advisory lock key, reporting false without
+// running fn when another session holds it. The lock is released when the transaction ends.
+func (p Postgres) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	locked := false
+	err := p.Transaction(ctx, func(ctx context.Context) error {
+		if err := p.Conn(ctx).Raw("SELECT pg_try_advisory_xact_lock(?)", key).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		return fn(ctx)
+	})
+
+	return locked, err
+}
+
+// WithSessionLock runs fn holding the advisory lock key on a dedicated connection, reporting false
+// without running fn when another session holds it. Unlike WithAdvisoryLock, fn is not wrapped in
+// a transaction, so it can commit its work in several. The lock is released when fn returns.
+func (p Postgres) WithSessionLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	db, err := p.DB.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	locked := false
+	if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil || !locked {
+		return false, err
+	}
+
+	err = fn(ctx)
+	_, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", key)
+	if unlockErr != nil {
+		// Drop the connection rather than return it to the pool still holding the lock.
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+
+	return true, errors.Join(err, unlockErr)
+}
+
+// Conn returns the transaction carried by ctx, or the connection pool when there is none.
+func (p Postgres) Conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+
+	return p.DB.WithContext(ctx)
+}
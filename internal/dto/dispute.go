@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Dispute struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	// Amount in minor units of Currency.
+	Amount int64 `json:"amount"`
+	// Currency defaults to, and must match, the currency of the transaction.
+	Currency string `json:"currency"`
+	// Status is opened, evidence_submitted, won or lost.
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// EvidenceDueAt defaults to the configured evidence window from now.
+	EvidenceDueAt time.Time      `json:"evidence_due_at"`
+	Evidence      []EvidenceFile `json:"evidence"`
+	ResolvedAt    *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// EvidenceFile is the metadata of a document stored at URL.
+type EvidenceFile struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	// Size in bytes.
+	Size int64  `json:"size"`
+	URL  string `json:"url"`
+	// SubmittedAt is set on submission and read only.
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// DisputeEvidence is the body of an evidence submission.
+type DisputeEvidence struct {
+	Files []EvidenceFile `json:"files"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransactionEvent struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	// Action is created, updated, deleted, authorized, captured, voided, authorization_expired,
+	// approved or declined.
+	Action string `json:"action"`
+	// Old is the transaction before the change, absent on creation.
+	Old json.RawMessage `json:"old,omitempty" swaggertype:"object"`
+	// New is the transaction after the change.
+	New       json.RawMessage `json:"new,omitempty" swaggertype:"object"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
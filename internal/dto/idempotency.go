@@ -0,0 +1,7 @@
+package dto
+
+// IdempotentResponse is the stored response replayed for a repeated Idempotency-Key.
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// JobRun is the outcome of the last run of a background job.
+type JobRun struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Processed counts the records the run changed.
+	Processed int64 `json:"processed"`
+	// Error is why the run stopped early. The records processed before it stay processed.
+	Error string `json:"error,omitempty"`
+}
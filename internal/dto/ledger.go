@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LedgerAccount struct {
+	ID       uuid.UUID `json:"id"`
+	Code     string    `json:"code"`
+	Currency string    `json:"currency"`
+	// Type is asset, revenue or contra_revenue.
+	Type string `json:"type"`
+	// Balance is the sum of the postings, debits positive and credits negative.
+	Balance int64 `json:"balance"`
+}
+
+type JournalEntry struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	EventType     string    `json:"event_type"`
+	CreatedAt     time.Time `json:"created_at"`
+	Postings      []Posting `json:"postings"`
+}
+
+type Posting struct {
+	AccountID uuid.UUID `json:"account_id"`
+	// Amount is a debit when positive and a credit when negative.
+	Amount int64 `json:"amount"`
+}
+
+// JournalFilter holds the query parameters accepted when listing journal entries.
+type JournalFilter struct {
+	TransactionID *uuid.UUID `query:"transaction_id"`
+	AccountID     *uuid.UUID `query:"account_id"`
+	Limit         int        `query:"limit"`
+}
+
+// LedgerCheck is the result of the ledger invariant checker.
+type LedgerCheck struct {
+	// UnbalancedEntries lists the journal entries whose postings do not sum to zero.
+	UnbalancedEntries []uuid.UUID `json:"unbalanced_entries"`
+	// Imbalance is the sum of all postings per currency, zero in a healthy ledger.
+	Imbalance map[string]int64 `json:"imbalance"`
+	CheckedAt time.Time        `json:"checked_at"`
+}
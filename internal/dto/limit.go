@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Limit struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Scope is merchant or customer.
+	Scope string `json:"scope"`
+	// SubjectID limits a single merchant or customer. Without it every one of them gets the limit.
+	SubjectID *uuid.UUID `json:"subject_id,omitempty"`
+	// Operation is create or capture.
+	Operation string `json:"operation"`
+	// Period is daily or monthly, in UTC.
+	Period string `json:"period"`
+	// MaxCount and MaxAmount, in minor units of the settlement currency, are not enforced when zero.
+	MaxCount  int64     `json:"max_count"`
+	MaxAmount int64     `json:"max_amount"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LimitUsageFilter selects the merchant or customer whose usage is reported.
+type LimitUsageFilter struct {
+	MerchantID *uuid.UUID `query:"merchant_id"`
+	CustomerID *uuid.UUID `query:"customer_id"`
+}
+
+// LimitUsage is what a merchant or customer consumed of a limit in the current period.
+type LimitUsage struct {
+	LimitID     uuid.UUID `json:"limit_id"`
+	Name        string    `json:"name"`
+	Scope       string    `json:"scope"`
+	SubjectID   uuid.UUID `json:"subject_id"`
+	Operation   string    `json:"operation"`
+	Period      string    `json:"period"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Count       int64     `json:"count"`
+	MaxCount    int64     `json:"max_count"`
+	// Amount and MaxAmount are in minor units of Currency, the settlement currency.
+	Amount    int64  `json:"amount"`
+	MaxAmount int64  `json:"max_amount"`
+	Currency  string `json:"currency"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type LineItem struct {
+	// SKU is required and at most 64 characters. Description is at most 255.
+	SKU         string `json:"sku"`
+	Description string `json:"description,omitempty"`
+	Quantity    int64  `json:"quantity"`
+	// UnitPrice, Tax and Discount are in minor units of the transaction currency. Tax and Discount
+	// apply to the whole line.
+	UnitPrice int64 `json:"unit_price"`
+	Tax       int64 `json:"tax"`
+	Discount  int64 `json:"discount"`
+	// Total is Quantity * UnitPrice + Tax - Discount. It is read only.
+	Total int64 `json:"total"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Merchant struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// ExternalReference is the identifier of the merchant in other systems, unique when set.
+	ExternalReference string    `json:"external_reference,omitempty"`
+	Email             string    `json:"email,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/google/uuid"
+
+// PageFilter holds the query parameters of listings paged by ID.
+type PageFilter struct {
+	// After is the ID of the last item of the previous page.
+	After uuid.UUID `query:"after"`
+	Limit int       `query:"limit"`
+}
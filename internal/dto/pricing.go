@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PricingRule struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Kind is fee or tax.
+	Kind string `json:"kind"`
+	// Type is percentage (rate_bps of the amount), fixed (fixed_amount) or tiered (the first tier
+	// whose up_to is at least the amount).
+	Type string `json:"type"`
+	// RateBps is a rate in basis points, 250 meaning 2.5%.
+	RateBps     int64         `json:"rate_bps,omitempty"`
+	FixedAmount int64         `json:"fixed_amount,omitempty"`
+	Tiers       []PricingTier `json:"tiers,omitempty"`
+	// Currency limits the rule to one currency. It is required by fixed and tiered rules.
+	Currency string `json:"currency,omitempty"`
+	// MerchantID limits the rule to one merchant. Merchant rules of a kind replace the general
+	// rules of that kind for the merchant.
+	MerchantID *uuid.UUID `json:"merchant_id,omitempty"`
+	Disabled   bool       `json:"disabled"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type PricingTier struct {
+	// UpTo is the largest amount of the tier, zero for no limit.
+	UpTo        int64 `json:"up_to"`
+	RateBps     int64 `json:"rate_bps"`
+	FixedAmount int64 `json:"fixed_amount"`
+}
+
+// Breakdown splits the amount of a transaction into fees, tax and the net amount left.
+type Breakdown struct {
+	Gross   int64    `json:"gross"`
+	Fees    int64    `json:"fees"`
+	Tax     int64    `json:"tax"`
+	Net     int64    `json:"net"`
+	Charges []Charge `json:"charges"`
+}
+
+type Charge struct {
+	RuleID uuid.UUID `json:"rule_id"`
+	Name   string    `json:"name"`
+	Kind   string    `json:"kind"`
+	Amount int64     `json:"amount"`
+}
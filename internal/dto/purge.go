@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// PurgeFilter holds the query parameters accepted when purging deleted transactions.
+type PurgeFilter struct {
+	// Retention is how long deleted transactions are kept, as a Go duration such as 720h. It
+	// defaults to the configured retention.
+	Retention string `query:"retention"`
+}
+
+// PurgeResult is the outcome of a purge.
+type PurgeResult struct {
+	// DeletedBefore is the cutoff: transactions deleted before it were purged.
+	DeletedBefore time.Time `json:"deleted_before"`
+	Purged        int64     `json:"purged"`
+}
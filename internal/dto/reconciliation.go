@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/google/uuid"
+
+const (
+	DiscrepancyMissing    = "missing"
+	DiscrepancyExtra      = "extra"
+	DiscrepancyMismatched = "mismatched"
+)
+
+// Discrepancy is a transaction whose Mongo document does not match Postgres.
+type Discrepancy struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	// Kind is missing (no document), extra (no row) or mismatched.
+	Kind string `json:"kind"`
+	// Fields lists the mismatched fields.
+	Fields []string `json:"fields,omitempty"`
+	// Repaired reports that the repair changed Mongo.
+	Repaired bool `json:"repaired"`
+}
+
+type ReconciliationSummary struct {
+	ScannedRows      int64 `json:"scanned_rows"`
+	ScannedDocuments int64 `json:"scanned_documents"`
+	Missing          int64 `json:"missing"`
+	Extra            int64 `json:"extra"`
+	Mismatched       int64 `json:"mismatched"`
+	Repaired         int64 `json:"repaired"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Refund struct {
+	ID            uuid.UUID `json:"id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	// Amount in minor units of Currency.
+	Amount int64 `json:"amount"`
+	// Currency defaults to, and must match, the currency of the transaction.
+	Currency string `json:"currency"`
+	// Status is pending, succeeded or failed.
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TotalsFilter holds the query parameters of the totals report.
+type TotalsFilter struct {
+	// Currency is the ISO-4217 code the totals are reported in.
+	Currency       string     `query:"currency"`
+	Status         string     `query:"status"`
+	MerchantID     *uuid.UUID `query:"merchant_id"`
+	CustomerID     *uuid.UUID `query:"customer_id"`
+	CreatedFrom    *time.Time `query:"created_from"`
+	CreatedTo      *time.Time `query:"created_to"`
+	IncludeDeleted bool       `query:"include_deleted"`
+}
+
+// Totals is the sum of the transaction amounts of every currency converted to Currency.
+type Totals struct {
+	Currency   string          `json:"currency"`
+	Amount     int64           `json:"amount"`
+	Count      int64           `json:"count"`
+	Currencies []CurrencyTotal `json:"currencies"`
+}
+
+// CurrencyTotal is the sum of the transaction amounts in one currency and its conversion at Rate.
+type CurrencyTotal struct {
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+	Count     int64  `json:"count"`
+	Rate      string `json:"rate"`
+	Converted int64  `json:"converted"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RiskRule struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Type is velocity (more than max_count transactions of the customer within window_seconds),
+	// amount (a settlement amount of at least threshold), blocklist (field is one of values) or
+	// currency (a currency outside values).
+	Type string `json:"type"`
+	// Score is added to the risk score of matching transactions, from 1 to 100.
+	Score         int   `json:"score"`
+	Threshold     int64 `json:"threshold,omitempty"`
+	MaxCount      int64 `json:"max_count,omitempty"`
+	WindowSeconds int64 `json:"window_seconds,omitempty"`
+	// Field is customer_id, merchant_id or metadata.<key>.
+	Field     string    `json:"field,omitempty"`
+	Values    []string  `json:"values,omitempty"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RiskAssessment struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Score         int       `json:"score"`
+	// Decision is approve, review or decline.
+	Decision string    `json:"decision"`
+	Hits     []RiskHit `json:"hits"`
+	// Resolution is approved or declined once a reviewed transaction has been resolved.
+	Resolution string     `json:"resolution,omitempty"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type RiskHit struct {
+	RuleID uuid.UUID `json:"rule_id"`
+	Name   string    `json:"name"`
+	Score  int       `json:"score"`
+}
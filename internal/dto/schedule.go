@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schedule creates a transaction with MerchantID, CustomerID, Amount, Currency, Metadata and Tags
+// every time Cron or Interval comes due between StartAt and EndAt.
+type Schedule struct {
+	ID         uuid.UUID         `json:"id"`
+	MerchantID *uuid.UUID        `json:"merchant_id,omitempty"`
+	CustomerID *uuid.UUID        `json:"customer_id,omitempty"`
+	Amount     int64             `json:"amount"`
+	Currency   string            `json:"currency"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	// Cron is a five field cron expression evaluated in UTC, such as 0 9 1 * * for 09:00 on the first
+	// of every month. Interval is a duration such as 720h. Exactly one of them is required.
+	Cron     string `json:"cron,omitempty" example:"0 9 1 * *"`
+	Interval string `json:"interval,omitempty" example:"720h"`
+	// CatchUp is all, the default, to create every run missed while the service was down, or latest
+	// to create only the most recent one.
+	CatchUp string `json:"catch_up" example:"all"`
+	// StartAt defaults to now and must not be in the past. The first run is at StartAt with an
+	// interval and at the first match of the cron expression from StartAt otherwise. No run happens
+	// after EndAt.
+	StartAt *time.Time `json:"start_at,omitempty"`
+	EndAt   *time.Time `json:"end_at,omitempty"`
+	// Status is active, paused, cancelled or completed. It is read only, like the fields below.
+	Status            string     `json:"status"`
+	NextRunAt         *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	RunCount          int64      `json:"run_count"`
+	LastTransactionID *uuid.UUID `json:"last_transaction_id,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ScheduleFilter holds the query parameters accepted when listing schedules.
+type ScheduleFilter struct {
+	MerchantID *uuid.UUID `query:"merchant_id"`
+	CustomerID *uuid.UUID `query:"customer_id"`
+	Status     string     `query:"status"`
+	Limit      int        `query:"limit"`
+}
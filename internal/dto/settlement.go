@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementBatch is the payout of the collected transactions of a merchant in one currency picked
+// up on BatchDate. Open batches report their running totals.
+type SettlementBatch struct {
+	ID         uuid.UUID `json:"id"`
+	MerchantID uuid.UUID `json:"merchant_id"`
+	Currency   string    `json:"currency"`
+	// BatchDate is the UTC day, formatted as YYYY-MM-DD.
+	BatchDate string `json:"batch_date" example:"2024-05-01"`
+	// Status is open or closed.
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+	// Net is Gross less Fees, Tax, Refunds and Chargebacks, all in minor units of Currency.
+	Gross       int64      `json:"gross"`
+	Fees        int64      `json:"fees"`
+	Tax         int64      `json:"tax"`
+	Refunds     int64      `json:"refunds"`
+	Chargebacks int64      `json:"chargebacks"`
+	Net         int64      `json:"net"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// SettlementBatchFilter holds the query parameters accepted when listing settlement batches.
+type SettlementBatchFilter struct {
+	MerchantID *uuid.UUID `query:"merchant_id"`
+	Status     string     `query:"status"`
+	// From and To bound BatchDate, both included, formatted as YYYY-MM-DD.
+	From  string `query:"from"`
+	To    string `query:"to"`
+	Limit int    `query:"limit"`
+}
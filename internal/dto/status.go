@@ -5,4 +5,8 @@ import "github.com/google/uuid"
 type Status struct {
 	Name string    `json:"name"`
 	ID   uuid.UUID `json:"id"`
+	// Transitions lists the statuses a transaction in this status may move to.
+	Transitions []string `json:"transitions"`
+	// Terminal is true when no transition leaves this status.
+	Terminal bool `json:"terminal"`
 }
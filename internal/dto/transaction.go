@@ -11,5 +11,101 @@ type Transaction struct {
 	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	Value     float64   `json:"value" binding:"required"`
+	// MerchantID is paid by CustomerID. Both are optional and cannot change after creation.
+	MerchantID *uuid.UUID `json:"merchant_id,omitempty"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	// Amount in minor units of Currency, e.g. cents for USD.
+	Amount int64 `json:"amount" binding:"required"`
+	// Currency is an ISO-4217 code.
+	Currency string `json:"currency" binding:"required"`
+	// Metadata holds up to 20 keys such as order or cart IDs. Keys are at most 40 characters and
+	// values at most 500.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Tags holds up to 20 distinct labels of at most 40 characters.
+	Tags []string `json:"tags,omitempty"`
+	// LineItems are the cart lines paid by the transaction. When present, Amount must equal the sum
+	// of their totals or be zero to have it computed.
+	LineItems []LineItem `json:"line_items,omitempty"`
+	// RefundedAmount is the sum of the succeeded refunds. It is read only.
+	RefundedAmount int64 `json:"refunded_amount"`
+	// ChargedBackAmount is the sum of the lost disputes. It is read only.
+	ChargedBackAmount int64 `json:"charged_back_amount"`
+	// AuthorizedAmount, CapturedAmount and VoidedAmount track the authorization hold. They are read only.
+	AuthorizedAmount int64 `json:"authorized_amount"`
+	CapturedAmount   int64 `json:"captured_amount"`
+	VoidedAmount     int64 `json:"voided_amount"`
+	// AuthorizationExpiresAt is when an uncaptured authorization is voided automatically.
+	AuthorizationExpiresAt *time.Time `json:"authorization_expires_at,omitempty"`
+	// Breakdown holds the fees and tax charged on Amount. It is read only.
+	Breakdown *Breakdown `json:"breakdown,omitempty"`
+	// Settlement is the amount converted to the settlement currency. It is read only.
+	Settlement *Settlement `json:"settlement,omitempty"`
+	// SplitType is installments or merchants once the transaction is split. ParentID is the split
+	// transaction a child belongs to, Installment numbers installments from 1 and DueAt is when one
+	// is due. They are read only.
+	SplitType   string     `json:"split_type,omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	Installment int        `json:"installment,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+}
+
+// Split is the body of a split. Type is installments or merchants.
+type Split struct {
+	Type string `json:"type"`
+	// Count splits into Count equal monthly installments, the first due at FirstDueAt or now, when
+	// Parts is empty.
+	Count      int        `json:"count,omitempty"`
+	FirstDueAt *time.Time `json:"first_due_at,omitempty"`
+	// Parts lists the children, whose amounts must sum to the amount of the transaction. Installments
+	// need a DueAt and merchant shares a MerchantID.
+	Parts []SplitPart `json:"parts,omitempty"`
+}
+
+type SplitPart struct {
+	Amount     int64      `json:"amount"`
+	MerchantID *uuid.UUID `json:"merchant_id,omitempty"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+}
+
+// Settlement is an amount converted to the settlement currency at Rate, the amount of the
+// settlement currency worth one unit of the transaction currency.
+type Settlement struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+	Rate     string `json:"rate"`
+}
+
+// Capture is the body of a capture. Zero captures the whole remaining authorization.
+type Capture struct {
+	Amount int64 `json:"amount"`
+}
+
+// TransactionFilter holds the query parameters accepted when listing transactions.
+type TransactionFilter struct {
+	// Cursor is the next_cursor of a previous page.
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+	// Sort is created_at, updated_at or amount, prefixed with - for descending order.
+	Sort           string     `query:"sort"`
+	Status         string     `query:"status"`
+	MerchantID     *uuid.UUID `query:"merchant_id"`
+	CustomerID     *uuid.UUID `query:"customer_id"`
+	Currency       string     `query:"currency"`
+	MinAmount      *int64     `query:"min_amount"`
+	MaxAmount      *int64     `query:"max_amount"`
+	CreatedFrom    *time.Time `query:"created_from"`
+	CreatedTo      *time.Time `query:"created_to"`
+	UpdatedFrom    *time.Time `query:"updated_from"`
+	UpdatedTo      *time.Time `query:"updated_to"`
+	IncludeDeleted bool       `query:"include_deleted"`
+	// Metadata lists key:value pairs that must all be present.
+	Metadata []string `query:"metadata"`
+	// Tags lists tags that must all be present.
+	Tags []string `query:"tag"`
+}
+
+type TransactionPage struct {
+	Items []Transaction `json:"items"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Customer is the party paying a transaction.
+type Customer struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name string    `gorm:"type:varchar(255);not null"`
+	// ExternalReference is the identifier of the customer in other systems, unique when set.
+	ExternalReference string `gorm:"type:varchar(255);uniqueIndex:idx_customers_external_reference,where:external_reference <> ''"`
+	Email             string `gorm:"type:varchar(255)"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dispute is a chargeback raised by the customer's bank against a collected transaction. Status is
+// opened, evidence_submitted, won or lost.
+type Dispute struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TransactionID uuid.UUID `gorm:"type:uuid;index;not null"`
+	Amount        int64     `gorm:"not null"`
+	Currency      string    `gorm:"type:varchar(3);not null"`
+	Status        string    `gorm:"type:varchar(32);index;not null"`
+	Reason        string    `gorm:"type:varchar(255)"`
+	// EvidenceDueAt is the deadline for evidence. A dispute still opened after it is lost.
+	EvidenceDueAt time.Time `gorm:"index;not null"`
+	Evidence      Evidence  `gorm:"default:'[]';not null"`
+	ResolvedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// EvidenceFile describes a document submitted in a dispute. The file itself is stored elsewhere,
+// at URL.
+type EvidenceFile struct {
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	URL         string    `json:"url"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Evidence is stored as a JSONB array in Postgres.
+type Evidence []EvidenceFile
+
+func (Evidence) GormDataType() string {
+	return "jsonb"
+}
+
+func (e Evidence) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(e)
+	return string(data), err
+}
+
+func (e *Evidence) Scan(value any) error {
+	return scanJSON(value, e)
+}
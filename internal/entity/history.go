@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionEvent is an append-only record of a change to a transaction. Old and New are JSON
+// snapshots of the transaction before and after the change.
+type TransactionEvent struct {
+	ID            uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TransactionID uuid.UUID       `gorm:"type:uuid;index;not null"`
+	Action        string          `gorm:"type:varchar(64);not null"`
+	Old           json.RawMessage `gorm:"type:jsonb"`
+	New           json.RawMessage `gorm:"type:jsonb"`
+	Actor         string          `gorm:"type:varchar(255);not null"`
+	RequestID     string          `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time       `gorm:"index;not null"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type IdempotencyKey struct {
+	Key         string    `gorm:"type:varchar(255);primaryKey"`
+	Fingerprint string    `gorm:"type:varchar(64);not null"`
+	StatusCode  int       `gorm:"default:0;not null"`
+	Body        []byte    `gorm:"type:bytea"`
+	CreatedAt   time.Time `gorm:"not null"`
+	// ClaimedAt is when the request handling the key started. A key that is still not completed a
+	// lease after it can be claimed again.
+	ClaimedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// JobRun is the outcome of the last run of a background job by the replica that ran it.
+type JobRun struct {
+	Name       string    `gorm:"type:varchar(64);primaryKey"`
+	StartedAt  time.Time `gorm:"not null"`
+	FinishedAt time.Time `gorm:"not null"`
+	// Processed counts the records the run changed.
+	Processed int64 `gorm:"default:0;not null"`
+	// Error is why the run stopped early, empty when it succeeded.
+	Error string `gorm:"type:text;default:'';not null"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerAccount is an account of the double-entry ledger. Accounts are kept per currency.
+type LedgerAccount struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Code     string    `gorm:"type:varchar(64);uniqueIndex:idx_ledger_accounts_code_currency;not null"`
+	Currency string    `gorm:"type:varchar(3);uniqueIndex:idx_ledger_accounts_code_currency;not null"`
+	// Type is asset, revenue or contra_revenue.
+	Type      string `gorm:"type:varchar(32);not null"`
+	CreatedAt time.Time
+}
+
+// JournalEntry groups the postings of one change. Its postings sum to zero.
+type JournalEntry struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TransactionID uuid.UUID `gorm:"type:uuid;index;not null"`
+	EventType     string    `gorm:"type:varchar(64);not null"`
+	CreatedAt     time.Time `gorm:"index;not null"`
+	Postings      []Posting `gorm:"foreignKey:JournalEntryID"`
+}
+
+// Posting moves Amount minor units into an account: positive amounts are debits and negative
+// amounts credits.
+type Posting struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	JournalEntryID uuid.UUID `gorm:"type:uuid;index;not null"`
+	AccountID      uuid.UUID `gorm:"type:uuid;index;not null"`
+	Amount         int64     `gorm:"not null"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limit caps the number and settlement amount of the transactions a merchant or customer creates
+// or captures per day or month. Limits without SubjectID apply to every merchant or customer.
+type Limit struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name string    `gorm:"type:varchar(255);not null"`
+	// Scope is merchant or customer.
+	Scope     string     `gorm:"type:varchar(16);not null;index:idx_limits_lookup"`
+	SubjectID *uuid.UUID `gorm:"type:uuid;index"`
+	// Operation is create or capture.
+	Operation string `gorm:"type:varchar(16);not null;index:idx_limits_lookup"`
+	// Period is daily or monthly, in UTC.
+	Period string `gorm:"type:varchar(16);not null"`
+	// MaxCount and MaxAmount are not enforced when zero.
+	MaxCount  int64 `gorm:"default:0;not null"`
+	MaxAmount int64 `gorm:"default:0;not null"`
+	Disabled  bool  `gorm:"default:false;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// LimitUsage counts what a subject consumed of a limit in the period starting at PeriodStart.
+type LimitUsage struct {
+	LimitID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubjectID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PeriodStart time.Time `gorm:"primaryKey"`
+	Count       int64     `gorm:"default:0;not null"`
+	Amount      int64     `gorm:"default:0;not null"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "github.com/google/uuid"
+
+// LineItem is a line of the cart a transaction pays for. Amounts are in minor units of the
+// transaction currency and Total is Quantity * UnitPrice + Tax - Discount.
+type LineItem struct {
+	ID            uuid.UUID `bson:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TransactionID uuid.UUID `bson:"-" gorm:"type:uuid;index;not null"`
+	Position      int       `bson:"position" gorm:"not null"`
+	SKU           string    `bson:"sku" gorm:"type:varchar(64);not null"`
+	Description   string    `bson:"description" gorm:"type:varchar(255)"`
+	Quantity      int64     `bson:"quantity" gorm:"not null"`
+	UnitPrice     int64     `bson:"unit_price" gorm:"not null"`
+	Tax           int64     `bson:"tax" gorm:"default:0;not null"`
+	Discount      int64     `bson:"discount" gorm:"default:0;not null"`
+	Total         int64     `bson:"total" gorm:"not null"`
+}
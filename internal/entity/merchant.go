@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Merchant is the party receiving the money of a transaction.
+type Merchant struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name string    `gorm:"type:varchar(255);not null"`
+	// ExternalReference is the identifier of the merchant in other systems, unique when set.
+	ExternalReference string `gorm:"type:varchar(255);uniqueIndex:idx_merchants_external_reference,where:external_reference <> ''"`
+	Email             string `gorm:"type:varchar(255)"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Metadata holds free-form key/value pairs, stored as JSONB in Postgres.
+type Metadata map[string]string
+
+func (Metadata) GormDataType() string {
+	return "jsonb"
+}
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(m)
+	return string(data), err
+}
+
+func (m *Metadata) Scan(value any) error {
+	return scanJSON(value, m)
+}
+
+// Tags holds a list of labels, stored as a JSONB array in Postgres.
+type Tags []string
+
+func (Tags) GormDataType() string {
+	return "jsonb"
+}
+
+func (t Tags) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(t)
+	return string(data), err
+}
+
+func (t *Tags) Scan(value any) error {
+	return scanJSON(value, t)
+}
+
+func scanJSON(value, dest any) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", value)
+	}
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a notification written in the same database transaction as the change it
+// describes and relayed to Kafka afterwards. ID is monotonic and defines the delivery order.
+// DeadAt is set when the event is given up on after too many failed attempts.
+type OutboxEvent struct {
+	ID            int64           `gorm:"primaryKey;autoIncrement"`
+	AggregateID   uuid.UUID       `gorm:"type:uuid;index;not null"`
+	EventType     string          `gorm:"type:varchar(255);not null"`
+	Payload       json.RawMessage `gorm:"type:jsonb;not null"`
+	CreatedAt     time.Time       `gorm:"not null"`
+	Attempts      int             `gorm:"default:0;not null"`
+	NextAttemptAt time.Time       `gorm:"not null"`
+	LastError     string          `gorm:"type:text"`
+	ProcessedAt   *time.Time      `gorm:"index:idx_outbox_events_pending,where:processed_at IS NULL"`
+	DeadAt        *time.Time
+}
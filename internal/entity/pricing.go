@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingRule computes a fee or a tax on the amount of transactions. Rules without Currency or
+// MerchantID apply to every currency or merchant.
+type PricingRule struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name string    `gorm:"type:varchar(255);not null"`
+	// Kind is fee or tax.
+	Kind string `gorm:"type:varchar(16);not null"`
+	// Type is percentage, fixed or tiered.
+	Type        string       `gorm:"type:varchar(16);not null"`
+	RateBps     int64        `gorm:"default:0;not null"`
+	FixedAmount int64        `gorm:"default:0;not null"`
+	Tiers       PricingTiers `gorm:"default:'[]';not null"`
+	Currency    string       `gorm:"type:varchar(3);default:'';not null;index"`
+	MerchantID  *uuid.UUID   `gorm:"type:uuid;index"`
+	Merchant    *Merchant    `gorm:"foreignKey:MerchantID"`
+	Disabled    bool         `gorm:"default:false;not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PricingTier applies to amounts up to UpTo, or to any amount when UpTo is zero.
+type PricingTier struct {
+	UpTo        int64 `json:"up_to"`
+	RateBps     int64 `json:"rate_bps"`
+	FixedAmount int64 `json:"fixed_amount"`
+}
+
+// PricingTiers is stored as a JSONB array in Postgres.
+type PricingTiers []PricingTier
+
+func (PricingTiers) GormDataType() string {
+	return "jsonb"
+}
+
+func (t PricingTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(t)
+	return string(data), err
+}
+
+func (t *PricingTiers) Scan(value any) error {
+	return scanJSON(value, t)
+}
+
+// Charge is the amount a pricing rule charged on a transaction. Name and Kind are copied from the
+// rule so that the breakdown survives changes to it.
+type Charge struct {
+	RuleID uuid.UUID `bson:"rule_id" json:"rule_id"`
+	Name   string    `bson:"name" json:"name"`
+	Kind   string    `bson:"kind" json:"kind"`
+	Amount int64     `bson:"amount" json:"amount"`
+}
+
+// Charges is stored as a JSONB array in Postgres.
+type Charges []Charge
+
+func (Charges) GormDataType() string {
+	return "jsonb"
+}
+
+func (c Charges) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *Charges) Scan(value any) error {
+	return scanJSON(value, c)
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectionTask marks a transaction whose Mongo document must be rewritten from Postgres.
+// MarkedAt changes on every new change so a projection only clears the mark it has seen.
+type ProjectionTask struct {
+	TransactionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	MarkedAt      time.Time `gorm:"index;not null"`
+	Attempts      int       `gorm:"default:0;not null"`
+	NextAttemptAt time.Time `gorm:"not null"`
+	LastError     string    `gorm:"type:text"`
+}
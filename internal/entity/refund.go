@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Refund returns part or all of the amount of a transaction. Status is pending, succeeded or failed.
+type Refund struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TransactionID uuid.UUID `gorm:"type:uuid;index;not null"`
+	Amount        int64     `gorm:"not null"`
+	Currency      string    `gorm:"type:varchar(3);not null"`
+	Status        string    `gorm:"type:varchar(32);index;not null"`
+	Reason        string    `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskRule adds Score to the risk score of the transactions it matches.
+type RiskRule struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name string    `gorm:"type:varchar(255);not null"`
+	// Type is velocity, amount, blocklist or currency.
+	Type  string `gorm:"type:varchar(16);not null"`
+	Score int    `gorm:"not null"`
+	// Threshold is the settlement amount from which amount rules match.
+	Threshold int64 `gorm:"default:0;not null"`
+	// MaxCount transactions of a customer within WindowSeconds are allowed by velocity rules.
+	MaxCount      int64 `gorm:"default:0;not null"`
+	WindowSeconds int64 `gorm:"default:0;not null"`
+	// Field is what blocklist rules compare with Values: customer_id, merchant_id or metadata.<key>.
+	Field string `gorm:"type:varchar(64);default:'';not null"`
+	// Values are the blocked values of blocklist rules and the usual currencies of currency rules.
+	Values    RiskValues `gorm:"default:'[]';not null"`
+	Disabled  bool       `gorm:"default:false;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RiskValues is stored as a JSONB array in Postgres.
+type RiskValues []string
+
+func (RiskValues) GormDataType() string {
+	return "jsonb"
+}
+
+func (v RiskValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func (v *RiskValues) Scan(value any) error {
+	return scanJSON(value, v)
+}
+
+// RiskAssessment is the result of screening a transaction when it was created.
+type RiskAssessment struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TransactionID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null"`
+	Score         int       `gorm:"not null"`
+	// Decision is approve, review or decline.
+	Decision string   `gorm:"type:varchar(16);not null;index"`
+	Hits     RiskHits `gorm:"default:'[]';not null"`
+	// Resolution is approved or declined once a reviewed transaction has been resolved.
+	Resolution string `gorm:"type:varchar(16);default:'';not null"`
+	ResolvedBy string `gorm:"type:varchar(255);default:'';not null"`
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// RiskHit is a rule that matched a transaction. Name is copied so that the assessment survives
+// changes to the rule.
+type RiskHit struct {
+	RuleID uuid.UUID `json:"rule_id"`
+	Name   string    `json:"name"`
+	Score  int       `json:"score"`
+}
+
+// RiskHits is stored as a JSONB array in Postgres.
+type RiskHits []RiskHit
+
+func (RiskHits) GormDataType() string {
+	return "jsonb"
+}
+
+func (h RiskHits) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(h)
+	return string(data), err
+}
+
+func (h *RiskHits) Scan(value any) error {
+	return scanJSON(value, h)
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schedule creates a transaction from its template every time Cron or Interval comes due between
+// StartAt and EndAt. Status is active, paused, cancelled or completed.
+type Schedule struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MerchantID *uuid.UUID `gorm:"type:uuid;index"`
+	CustomerID *uuid.UUID `gorm:"type:uuid;index"`
+	Amount     int64      `gorm:"not null"`
+	Currency   string     `gorm:"type:varchar(3);not null"`
+	Metadata   Metadata   `gorm:"default:'{}';not null"`
+	Tags       Tags       `gorm:"default:'[]';not null"`
+	// Cron is a five field cron expression in UTC and Interval a duration such as 720h. Exactly one
+	// of them is set.
+	Cron     string `gorm:"type:varchar(100);default:'';not null"`
+	Interval string `gorm:"type:varchar(32);default:'';not null"`
+	// CatchUp is all to create every run missed while the service was down, or latest to create only
+	// the most recent one.
+	CatchUp string    `gorm:"type:varchar(16);not null"`
+	StartAt time.Time `gorm:"not null"`
+	EndAt   *time.Time
+	Status  string `gorm:"type:varchar(16);index;not null"`
+	// NextRunAt is when the next transaction is due. It is empty once the schedule is cancelled or
+	// completed.
+	NextRunAt         *time.Time `gorm:"index"`
+	LastRunAt         *time.Time
+	RunCount          int64      `gorm:"default:0;not null"`
+	LastTransactionID *uuid.UUID `gorm:"type:uuid"`
+	// LastError is why the last run could not create its transaction, empty after a successful run.
+	LastError string `gorm:"type:text;default:'';not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
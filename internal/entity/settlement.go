@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementBatch groups the collected transactions of a merchant in one currency picked up on one
+// day. Status is open until the day is over and closed afterwards, when the totals are fixed.
+type SettlementBatch struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MerchantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_settlement_batches_day"`
+	Currency   string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_settlement_batches_day"`
+	BatchDate  time.Time `gorm:"type:date;not null;uniqueIndex:idx_settlement_batches_day"`
+	Status     string    `gorm:"type:varchar(32);index;not null"`
+	// Count and the amounts are stored when the batch closes, in minor units of Currency.
+	Count       int64 `gorm:"default:0;not null"`
+	Gross       int64 `gorm:"default:0;not null"`
+	Fees        int64 `gorm:"default:0;not null"`
+	Tax         int64 `gorm:"default:0;not null"`
+	Refunds     int64 `gorm:"default:0;not null"`
+	Chargebacks int64 `gorm:"default:0;not null"`
+	Net         int64 `gorm:"default:0;not null"`
+	ClosedAt    *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SettlementEntry is what a settlement batch paid out for one transaction. Closing a batch records
+// an entry for each of its transactions; refunds and chargebacks made afterwards are booked as
+// adjustment entries in an open batch. The amounts are in minor units of the batch currency.
+type SettlementEntry struct {
+	ID                   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	BatchID              uuid.UUID `gorm:"type:uuid;index;not null"`
+	TransactionID        uuid.UUID `gorm:"type:uuid;index;not null"`
+	Adjustment           bool      `gorm:"default:false;not null"`
+	Status               string    `gorm:"type:varchar(32);not null"`
+	TransactionCreatedAt time.Time
+	Gross                int64 `gorm:"default:0;not null"`
+	Fees                 int64 `gorm:"default:0;not null"`
+	Tax                  int64 `gorm:"default:0;not null"`
+	Refunds              int64 `gorm:"default:0;not null"`
+	Chargebacks          int64 `gorm:"default:0;not null"`
+	CreatedAt            time.Time
+}
@@ -5,4 +5,5 @@ import "github.com/google/uuid"
 type Status struct {
 	ID   uuid.UUID `bson:"_id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	Name string    `bson:"name" gorm:"type:varchar(255);not null;unique"`
+	Next []Status  `bson:"-" gorm:"many2many:status_transitions;joinForeignKey:FromStatusID;joinReferences:ToStatusID"`
 }
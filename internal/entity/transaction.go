@@ -9,11 +9,52 @@ import (
 
 type Transaction struct {
 	ID        uuid.UUID      `bson:"_id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	CreatedAt time.Time      `bson:"created_at"`
-	UpdatedAt time.Time      `bson:"updated_at"`
+	CreatedAt time.Time      `bson:"created_at" gorm:"index"`
+	UpdatedAt time.Time      `bson:"updated_at" gorm:"index"`
 	DeletedAt gorm.DeletedAt `bson:"deleted_at" gorm:"index"`
 	IsDeleted bool           `bson:"is_deleted" gorm:"default:false"`
-	StatusID  uuid.UUID      `bson:"-" gorm:"type:uuid"`
+	StatusID  uuid.UUID      `bson:"-" gorm:"type:uuid;index"`
 	Status    Status         `bson:"status" gorm:"foreignKey:StatusID;references:ID"`
-	Value     float64        `bson:"value" gorm:"default:0;notnull"`
+	// MerchantID is paid by CustomerID. Both are optional.
+	MerchantID *uuid.UUID `bson:"merchant_id" gorm:"type:uuid;index"`
+	Merchant   *Merchant  `bson:"-" gorm:"foreignKey:MerchantID"`
+	CustomerID *uuid.UUID `bson:"customer_id" gorm:"type:uuid;index"`
+	Customer   *Customer  `bson:"-" gorm:"foreignKey:CustomerID"`
+	Amount     int64      `bson:"amount" gorm:"default:0;notnull"`
+	Currency   string     `bson:"currency" gorm:"type:varchar(3);default:'';notnull"`
+	Version    int64      `bson:"version" gorm:"default:1;notnull"`
+	Metadata   Metadata   `bson:"metadata" gorm:"default:'{}';notnull;index:,type:gin"`
+	Tags       Tags       `bson:"tags" gorm:"default:'[]';notnull;index:,type:gin"`
+	LineItems  []LineItem `bson:"line_items" gorm:"foreignKey:TransactionID"`
+	// RefundedAmount is the sum of the succeeded refunds, in minor units of Currency.
+	RefundedAmount int64 `bson:"refunded_amount" gorm:"default:0;notnull"`
+	// ChargedBackAmount is the sum of the lost disputes, in minor units of Currency.
+	ChargedBackAmount int64 `bson:"charged_back_amount" gorm:"default:0;notnull"`
+	// AuthorizedAmount is held by an authorization; CapturedAmount and VoidedAmount are the parts of
+	// it that were captured and released. Zero when the transaction was never authorized.
+	AuthorizedAmount       int64      `bson:"authorized_amount" gorm:"default:0;notnull"`
+	CapturedAmount         int64      `bson:"captured_amount" gorm:"default:0;notnull"`
+	VoidedAmount           int64      `bson:"voided_amount" gorm:"default:0;notnull"`
+	AuthorizationExpiresAt *time.Time `bson:"authorization_expires_at" gorm:"index"`
+	// FeeAmount and TaxAmount are charged on Amount by the pricing rules listed in Charges, leaving
+	// NetAmount.
+	FeeAmount int64   `bson:"fee_amount" gorm:"default:0;notnull"`
+	TaxAmount int64   `bson:"tax_amount" gorm:"default:0;notnull"`
+	NetAmount int64   `bson:"net_amount" gorm:"default:0;notnull"`
+	Charges   Charges `bson:"charges" gorm:"default:'[]';notnull"`
+	// SettlementAmount is Amount converted to SettlementCurrency at FxRate, the amount of
+	// SettlementCurrency worth one unit of Currency. Empty on transactions created before FX.
+	SettlementCurrency string `bson:"settlement_currency" gorm:"type:varchar(3);default:'';notnull"`
+	SettlementAmount   int64  `bson:"settlement_amount" gorm:"default:0;notnull"`
+	FxRate             string `bson:"fx_rate" gorm:"type:varchar(32);default:'';notnull"`
+	// SplitType is installments or merchants once the transaction is split into children, which
+	// point back to it with ParentID. Installment numbers installments from 1 and DueAt is when one
+	// is due.
+	SplitType   string     `bson:"split_type" gorm:"type:varchar(32);default:'';notnull"`
+	ParentID    *uuid.UUID `bson:"parent_id" gorm:"type:uuid;index"`
+	Installment int        `bson:"installment" gorm:"default:0;notnull"`
+	DueAt       *time.Time `bson:"due_at"`
+	// SettlementBatchID is the settlement batch that paid the transaction out. It is kept in
+	// Postgres only.
+	SettlementBatchID *uuid.UUID `bson:"-" gorm:"type:uuid;index"`
 }
@@ -0,0 +1,94 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+)
+
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// FileProvider serves the exchange rates of a JSON file such as
+//
+//	{"base": "USD", "rates": {"EUR": "0.92", "JPY": "151.3"}}
+//
+// where each rate is the amount of a currency worth one unit of base. Rates between two quoted
+// currencies are crossed through base.
+type FileProvider struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+type rateFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+// NewFileProvider loads the rates of path. Without a path it only converts a currency to itself.
+func NewFileProvider(path string) (*FileProvider, error) {
+	provider := &FileProvider{rates: map[string]*big.Rat{}}
+	if path == "" {
+		return provider, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file rateFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	provider.base = currency.Normalize(file.Base)
+	if err = currency.Validate(provider.base); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for code, value := range file.Rates {
+		code = currency.Normalize(code)
+		if err = currency.Validate(code); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("parse %s: invalid rate %q for %s", path, value, code)
+		}
+		provider.rates[code] = rate
+	}
+
+	return provider, nil
+}
+
+// Rate returns the amount of to worth one unit of from.
+func (p *FileProvider) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	fromRate, ok := p.baseRate(from)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrRateUnavailable, from, to)
+	}
+	toRate, ok := p.baseRate(to)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrRateUnavailable, from, to)
+	}
+
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+func (p *FileProvider) baseRate(code string) (*big.Rat, bool) {
+	if code == p.base && p.base != "" {
+		return big.NewRat(1, 1), true
+	}
+
+	rate, ok := p.rates[code]
+	return rate, ok
+}
@@ -0,0 +1,380 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrTestFailed   = errors.New("test failed")
+)
+
+// Merge applies an RFC 7396 merge patch to doc: the members of a patch object replace those of doc,
+// recursively, and null members remove them. Any other patch replaces doc.
+func Merge(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+
+	for key, value := range changes {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = merge(object[key], value)
+	}
+
+	return object
+}
+
+// operation is one operation of an RFC 6902 JSON Patch. Value is nil when the member is absent.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply applies an RFC 6902 JSON Patch to doc. The operations apply in order and the patch fails as
+// a whole when one of them does, with ErrTestFailed when a test operation does not match.
+func Apply(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []operation
+	if err = json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: a JSON Patch is an array of operations", ErrInvalidPatch)
+	}
+
+	for i, op := range operations {
+		if target, err = op.apply(target); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func (op operation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: %s has no path", ErrInvalidPatch, op.Op)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: %s has no value", ErrInvalidPatch, op.Op)
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+		}
+		return op.applyValue(doc, path, value)
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: %s has no from", ErrInvalidPatch, op.Op)
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		return op.applyFrom(doc, path, from)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+func (op operation) applyValue(doc any, path []string, value any) (any, error) {
+	switch op.Op {
+	case "add":
+		return add(doc, path, value)
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return edit(doc, path, func(parent any, token string) (any, error) {
+			return set(parent, token, value)
+		})
+	default:
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, fmt.Errorf("%w: %q is not %s", ErrTestFailed, *op.Path, op.Value)
+		}
+		return doc, nil
+	}
+}
+
+func (op operation) applyFrom(doc any, path, from []string) (any, error) {
+	value, err := get(doc, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Op == "copy" {
+		return add(doc, path, clone(value))
+	}
+	if *op.Path != *op.From && strings.HasPrefix(*op.Path, *op.From+"/") {
+		return nil, fmt.Errorf("%w: cannot move %q into itself", ErrInvalidPatch, *op.From)
+	}
+	if doc, err = remove(doc, from); err != nil {
+		return nil, err
+	}
+	return add(doc, path, value)
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return edit(doc, path, func(parent any, token string) (any, error) {
+		array, ok := parent.([]any)
+		if !ok {
+			return set(parent, token, value)
+		}
+
+		i := len(array)
+		if token != "-" {
+			var err error
+			if i, err = index(token, len(array)+1); err != nil {
+				return nil, err
+			}
+		}
+		return append(array[:i], append([]any{value}, array[i:]...)...), nil
+	})
+}
+
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+
+	return edit(doc, path, func(parent any, token string) (any, error) {
+		switch parent := parent.(type) {
+		case map[string]any:
+			if _, ok := parent[token]; !ok {
+				return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+			}
+			delete(parent, token)
+			return parent, nil
+		case []any:
+			i, err := index(token, len(parent))
+			if err != nil {
+				return nil, err
+			}
+			return append(parent[:i], parent[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("%w: %q is not in an object or array", ErrInvalidPatch, token)
+		}
+	})
+}
+
+// edit replaces the parent of the value at the non-empty path with what fn returns for it and the
+// last token of path, and returns the edited doc.
+func edit(doc any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = edit(child, path[1:], fn); err != nil {
+		return nil, err
+	}
+
+	return set(doc, path[0], child)
+}
+
+// set replaces the member token of an object, adding it if needed, or an existing element of an
+// array.
+func set(parent any, token string, value any) (any, error) {
+	switch parent := parent.(type) {
+	case map[string]any:
+		parent[token] = value
+		return parent, nil
+	case []any:
+		i, err := index(token, len(parent))
+		if err != nil {
+			return nil, err
+		}
+		parent[i] = value
+		return parent, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not in an object or array", ErrInvalidPatch, token)
+	}
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, token)
+			}
+			doc = value
+		case []any:
+			i, err := index(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %q is not in an object or array", ErrInvalidPatch, token)
+		}
+	}
+
+	return doc, nil
+}
+
+// index parses an array index token, which must be below limit.
+func index(token string, limit int) (int, error) {
+	i, err := strconv.ParseUint(token, 10, 0)
+	if err != nil || i >= uint64(limit) || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: index %q out of range", ErrInvalidPatch, token)
+	}
+
+	return int(i), nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// decode decodes JSON keeping numbers exact.
+func decode(data []byte) (any, error) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	return value, nil
+}
+
+func clone(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(value))
+		for key, member := range value {
+			object[key] = clone(member)
+		}
+		return object
+	case []any:
+		array := make([]any, len(value))
+		for i, element := range value {
+			array[i] = clone(element)
+		}
+		return array
+	default:
+		return value
+	}
+}
+
+// equal compares JSON values, numbers by value.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		object, ok := b.(map[string]any)
+		return ok && equalObjects(a, object)
+	case []any:
+		array, ok := b.([]any)
+		return ok && equalArrays(a, array)
+	case json.Number:
+		number, ok := b.(json.Number)
+		return ok && equalNumbers(a, number)
+	default:
+		return a == b
+	}
+}
+
+func equalObjects(a, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, member := range a {
+		other, ok := b[key]
+		if !ok || !equal(member, other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalArrays(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalNumbers(a, b json.Number) bool {
+	x, xOK := new(big.Rat).SetString(a.String())
+	y, yOK := new(big.Rat).SetString(b.String())
+
+	return xOK && yOK && x.Cmp(y) == 0
+}
@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type CustomerMapper struct {
+}
+
+func NewCustomerMapper() *CustomerMapper {
+	return &CustomerMapper{}
+}
+
+func (*CustomerMapper) ToDTO(customer *entity.Customer) *dto.Customer {
+	return &dto.Customer{
+		ID:                customer.ID,
+		Name:              customer.Name,
+		ExternalReference: customer.ExternalReference,
+		Email:             customer.Email,
+		CreatedAt:         customer.CreatedAt,
+		UpdatedAt:         customer.UpdatedAt,
+	}
+}
+func (*CustomerMapper) FromDTO(customer *dto.Customer) *entity.Customer {
+	return &entity.Customer{
+		ID:                customer.ID,
+		Name:              customer.Name,
+		ExternalReference: customer.ExternalReference,
+		Email:             customer.Email,
+		CreatedAt:         customer.CreatedAt,
+		UpdatedAt:         customer.UpdatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type DisputeMapper struct {
+}
+
+func NewDisputeMapper() *DisputeMapper {
+	return &DisputeMapper{}
+}
+
+func (*DisputeMapper) ToDTO(dispute *entity.Dispute) *dto.Dispute {
+	evidence := make([]dto.EvidenceFile, len(dispute.Evidence))
+	for i, file := range dispute.Evidence {
+		evidence[i] = dto.EvidenceFile(file)
+	}
+
+	return &dto.Dispute{
+		ID:            dispute.ID,
+		TransactionID: dispute.TransactionID,
+		Amount:        dispute.Amount,
+		Currency:      dispute.Currency,
+		Status:        dispute.Status,
+		Reason:        dispute.Reason,
+		EvidenceDueAt: dispute.EvidenceDueAt,
+		Evidence:      evidence,
+		ResolvedAt:    dispute.ResolvedAt,
+		CreatedAt:     dispute.CreatedAt,
+		UpdatedAt:     dispute.UpdatedAt,
+	}
+}
+
+func (*DisputeMapper) EvidenceFromDTO(files []dto.EvidenceFile) entity.Evidence {
+	evidence := make(entity.Evidence, len(files))
+	for i, file := range files {
+		evidence[i] = entity.EvidenceFile(file)
+	}
+
+	return evidence
+}
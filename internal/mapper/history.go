@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type HistoryMapper struct {
+}
+
+func NewHistoryMapper() *HistoryMapper {
+	return &HistoryMapper{}
+}
+
+func (*HistoryMapper) ToDTO(event *entity.TransactionEvent) *dto.TransactionEvent {
+	return &dto.TransactionEvent{
+		ID:            event.ID,
+		TransactionID: event.TransactionID,
+		Action:        event.Action,
+		Old:           event.Old,
+		New:           event.New,
+		Actor:         event.Actor,
+		RequestID:     event.RequestID,
+		CreatedAt:     event.CreatedAt,
+	}
+}
@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type JobRunMapper struct {
+}
+
+func NewJobRunMapper() *JobRunMapper {
+	return &JobRunMapper{}
+}
+
+func (*JobRunMapper) ToDTO(run *entity.JobRun) *dto.JobRun {
+	return &dto.JobRun{
+		Name:       run.Name,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Processed:  run.Processed,
+		Error:      run.Error,
+	}
+}
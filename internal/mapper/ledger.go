@@ -0,0 +1,38 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type LedgerMapper struct {
+}
+
+func NewLedgerMapper() *LedgerMapper {
+	return &LedgerMapper{}
+}
+
+func (*LedgerMapper) AccountToDTO(account *entity.LedgerAccount, balance int64) *dto.LedgerAccount {
+	return &dto.LedgerAccount{
+		ID:       account.ID,
+		Code:     account.Code,
+		Currency: account.Currency,
+		Type:     account.Type,
+		Balance:  balance,
+	}
+}
+
+func (*LedgerMapper) EntryToDTO(entry *entity.JournalEntry) *dto.JournalEntry {
+	postings := make([]dto.Posting, len(entry.Postings))
+	for i, posting := range entry.Postings {
+		postings[i] = dto.Posting{AccountID: posting.AccountID, Amount: posting.Amount}
+	}
+
+	return &dto.JournalEntry{
+		ID:            entry.ID,
+		TransactionID: entry.TransactionID,
+		EventType:     entry.EventType,
+		CreatedAt:     entry.CreatedAt,
+		Postings:      postings,
+	}
+}
@@ -0,0 +1,44 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type LimitMapper struct {
+}
+
+func NewLimitMapper() *LimitMapper {
+	return &LimitMapper{}
+}
+
+func (*LimitMapper) ToDTO(limit *entity.Limit) *dto.Limit {
+	return &dto.Limit{
+		ID:        limit.ID,
+		Name:      limit.Name,
+		Scope:     limit.Scope,
+		SubjectID: limit.SubjectID,
+		Operation: limit.Operation,
+		Period:    limit.Period,
+		MaxCount:  limit.MaxCount,
+		MaxAmount: limit.MaxAmount,
+		Disabled:  limit.Disabled,
+		CreatedAt: limit.CreatedAt,
+		UpdatedAt: limit.UpdatedAt,
+	}
+}
+func (*LimitMapper) FromDTO(limit *dto.Limit) *entity.Limit {
+	return &entity.Limit{
+		ID:        limit.ID,
+		Name:      limit.Name,
+		Scope:     limit.Scope,
+		SubjectID: limit.SubjectID,
+		Operation: limit.Operation,
+		Period:    limit.Period,
+		MaxCount:  limit.MaxCount,
+		MaxAmount: limit.MaxAmount,
+		Disabled:  limit.Disabled,
+		CreatedAt: limit.CreatedAt,
+		UpdatedAt: limit.UpdatedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type MerchantMapper struct {
+}
+
+func NewMerchantMapper() *MerchantMapper {
+	return &MerchantMapper{}
+}
+
+func (*MerchantMapper) ToDTO(merchant *entity.Merchant) *dto.Merchant {
+	return &dto.Merchant{
+		ID:                merchant.ID,
+		Name:              merchant.Name,
+		ExternalReference: merchant.ExternalReference,
+		Email:             merchant.Email,
+		CreatedAt:         merchant.CreatedAt,
+		UpdatedAt:         merchant.UpdatedAt,
+	}
+}
+func (*MerchantMapper) FromDTO(merchant *dto.Merchant) *entity.Merchant {
+	return &entity.Merchant{
+		ID:                merchant.ID,
+		Name:              merchant.Name,
+		ExternalReference: merchant.ExternalReference,
+		Email:             merchant.Email,
+		CreatedAt:         merchant.CreatedAt,
+		UpdatedAt:         merchant.UpdatedAt,
+	}
+}
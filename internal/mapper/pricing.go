@@ -0,0 +1,56 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type PricingMapper struct {
+}
+
+func NewPricingMapper() *PricingMapper {
+	return &PricingMapper{}
+}
+
+func (*PricingMapper) ToDTO(rule *entity.PricingRule) *dto.PricingRule {
+	tiers := make([]dto.PricingTier, len(rule.Tiers))
+	for i, tier := range rule.Tiers {
+		tiers[i] = dto.PricingTier(tier)
+	}
+
+	return &dto.PricingRule{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Kind:        rule.Kind,
+		Type:        rule.Type,
+		RateBps:     rule.RateBps,
+		FixedAmount: rule.FixedAmount,
+		Tiers:       tiers,
+		Currency:    rule.Currency,
+		MerchantID:  rule.MerchantID,
+		Disabled:    rule.Disabled,
+		CreatedAt:   rule.CreatedAt,
+		UpdatedAt:   rule.UpdatedAt,
+	}
+}
+func (*PricingMapper) FromDTO(rule *dto.PricingRule) *entity.PricingRule {
+	tiers := make(entity.PricingTiers, len(rule.Tiers))
+	for i, tier := range rule.Tiers {
+		tiers[i] = entity.PricingTier(tier)
+	}
+
+	return &entity.PricingRule{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Kind:        rule.Kind,
+		Type:        rule.Type,
+		RateBps:     rule.RateBps,
+		FixedAmount: rule.FixedAmount,
+		Tiers:       tiers,
+		Currency:    rule.Currency,
+		MerchantID:  rule.MerchantID,
+		Disabled:    rule.Disabled,
+		CreatedAt:   rule.CreatedAt,
+		UpdatedAt:   rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type RefundMapper struct {
+}
+
+func NewRefundMapper() *RefundMapper {
+	return &RefundMapper{}
+}
+
+func (*RefundMapper) ToDTO(refund *entity.Refund) *dto.Refund {
+	return &dto.Refund{
+		ID:            refund.ID,
+		TransactionID: refund.TransactionID,
+		Amount:        refund.Amount,
+		Currency:      refund.Currency,
+		Status:        refund.Status,
+		Reason:        refund.Reason,
+		CreatedAt:     refund.CreatedAt,
+		UpdatedAt:     refund.UpdatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type RiskMapper struct {
+}
+
+func NewRiskMapper() *RiskMapper {
+	return &RiskMapper{}
+}
+
+func (*RiskMapper) RuleToDTO(rule *entity.RiskRule) *dto.RiskRule {
+	return &dto.RiskRule{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		Type:          rule.Type,
+		Score:         rule.Score,
+		Threshold:     rule.Threshold,
+		MaxCount:      rule.MaxCount,
+		WindowSeconds: rule.WindowSeconds,
+		Field:         rule.Field,
+		Values:        rule.Values,
+		Disabled:      rule.Disabled,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
+func (*RiskMapper) RuleFromDTO(rule *dto.RiskRule) *entity.RiskRule {
+	return &entity.RiskRule{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		Type:          rule.Type,
+		Score:         rule.Score,
+		Threshold:     rule.Threshold,
+		MaxCount:      rule.MaxCount,
+		WindowSeconds: rule.WindowSeconds,
+		Field:         rule.Field,
+		Values:        rule.Values,
+		Disabled:      rule.Disabled,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
+
+func (*RiskMapper) AssessmentToDTO(assessment *entity.RiskAssessment) *dto.RiskAssessment {
+	hits := make([]dto.RiskHit, len(assessment.Hits))
+	for i, hit := range assessment.Hits {
+		hits[i] = dto.RiskHit(hit)
+	}
+
+	return &dto.RiskAssessment{
+		TransactionID: assessment.TransactionID,
+		Score:         assessment.Score,
+		Decision:      assessment.Decision,
+		Hits:          hits,
+		Resolution:    assessment.Resolution,
+		ResolvedBy:    assessment.ResolvedBy,
+		ResolvedAt:    assessment.ResolvedAt,
+		CreatedAt:     assessment.CreatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type ScheduleMapper struct {
+}
+
+func NewScheduleMapper() *ScheduleMapper {
+	return &ScheduleMapper{}
+}
+
+func (*ScheduleMapper) ToDTO(schedule *entity.Schedule) *dto.Schedule {
+	startAt := schedule.StartAt
+
+	return &dto.Schedule{
+		ID:                schedule.ID,
+		MerchantID:        schedule.MerchantID,
+		CustomerID:        schedule.CustomerID,
+		Amount:            schedule.Amount,
+		Currency:          schedule.Currency,
+		Metadata:          schedule.Metadata,
+		Tags:              schedule.Tags,
+		Cron:              schedule.Cron,
+		Interval:          schedule.Interval,
+		CatchUp:           schedule.CatchUp,
+		StartAt:           &startAt,
+		EndAt:             schedule.EndAt,
+		Status:            schedule.Status,
+		NextRunAt:         schedule.NextRunAt,
+		LastRunAt:         schedule.LastRunAt,
+		RunCount:          schedule.RunCount,
+		LastTransactionID: schedule.LastTransactionID,
+		LastError:         schedule.LastError,
+		CreatedAt:         schedule.CreatedAt,
+		UpdatedAt:         schedule.UpdatedAt,
+	}
+}
+
+// FromDTO maps the fields a client sets. StartAt defaults to now.
+func (*ScheduleMapper) FromDTO(schedule *dto.Schedule) *entity.Schedule {
+	startAt := time.Now()
+	if schedule.StartAt != nil {
+		startAt = *schedule.StartAt
+	}
+
+	return &entity.Schedule{
+		MerchantID: schedule.MerchantID,
+		CustomerID: schedule.CustomerID,
+		Amount:     schedule.Amount,
+		Currency:   schedule.Currency,
+		Metadata:   schedule.Metadata,
+		Tags:       schedule.Tags,
+		Cron:       schedule.Cron,
+		Interval:   schedule.Interval,
+		CatchUp:    schedule.CatchUp,
+		StartAt:    startAt,
+		EndAt:      schedule.EndAt,
+	}
+}
@@ -0,0 +1,33 @@
+package mapper
+
+import (
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type SettlementMapper struct {
+}
+
+func NewSettlementMapper() *SettlementMapper {
+	return &SettlementMapper{}
+}
+
+func (*SettlementMapper) ToDTO(batch *entity.SettlementBatch) *dto.SettlementBatch {
+	return &dto.SettlementBatch{
+		ID:          batch.ID,
+		MerchantID:  batch.MerchantID,
+		Currency:    batch.Currency,
+		BatchDate:   batch.BatchDate.Format("2006-01-02"),
+		Status:      batch.Status,
+		Count:       batch.Count,
+		Gross:       batch.Gross,
+		Fees:        batch.Fees,
+		Tax:         batch.Tax,
+		Refunds:     batch.Refunds,
+		Chargebacks: batch.Chargebacks,
+		Net:         batch.Net,
+		ClosedAt:    batch.ClosedAt,
+		CreatedAt:   batch.CreatedAt,
+		UpdatedAt:   batch.UpdatedAt,
+	}
+}
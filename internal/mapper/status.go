@@ -13,14 +13,27 @@ func NewStatusMapper() *StatusMapper {
 }
 
 func (*StatusMapper) ToDTO(transaction *entity.Status) *dto.Status {
+	transitions := make([]string, len(transaction.Next))
+	for i := range transaction.Next {
+		transitions[i] = transaction.Next[i].Name
+	}
+
 	return &dto.Status{
-		ID:   transaction.ID,
-		Name: transaction.Name,
+		ID:          transaction.ID,
+		Name:        transaction.Name,
+		Transitions: transitions,
+		Terminal:    len(transitions) == 0,
 	}
 }
 func (*StatusMapper) FromDTO(transaction *dto.Status) *entity.Status {
+	next := make([]entity.Status, len(transaction.Transitions))
+	for i, name := range transaction.Transitions {
+		next[i] = entity.Status{Name: name}
+	}
+
 	return &entity.Status{
 		ID:   transaction.ID,
 		Name: transaction.Name,
+		Next: next,
 	}
 }
@@ -14,21 +14,149 @@ func NewTransactionMapper() *TransactionMapper {
 
 func (*TransactionMapper) ToDTO(transaction *entity.Transaction) *dto.Transaction {
 	return &dto.Transaction{
-		ID:        transaction.ID,
-		Status:    transaction.Status.Name,
-		CreatedAt: transaction.CreatedAt,
-		UpdatedAt: transaction.UpdatedAt,
-		Value:     transaction.Value,
+		ID:             transaction.ID,
+		Status:         transaction.Status.Name,
+		CreatedAt:      transaction.CreatedAt,
+		UpdatedAt:      transaction.UpdatedAt,
+		MerchantID:     transaction.MerchantID,
+		CustomerID:     transaction.CustomerID,
+		Amount:         transaction.Amount,
+		Currency:       transaction.Currency,
+		Metadata:       transaction.Metadata,
+		Tags:           transaction.Tags,
+		LineItems:      lineItemsToDTO(transaction.LineItems),
+		RefundedAmount: transaction.RefundedAmount,
+
+		ChargedBackAmount:      transaction.ChargedBackAmount,
+		AuthorizedAmount:       transaction.AuthorizedAmount,
+		CapturedAmount:         transaction.CapturedAmount,
+		VoidedAmount:           transaction.VoidedAmount,
+		AuthorizationExpiresAt: transaction.AuthorizationExpiresAt,
+		Breakdown:              breakdownToDTO(transaction),
+		Settlement:             settlementToDTO(transaction),
+		SplitType:              transaction.SplitType,
+		ParentID:               transaction.ParentID,
+		Installment:            transaction.Installment,
+		DueAt:                  transaction.DueAt,
 	}
 }
 func (*TransactionMapper) FromDTO(transaction *dto.Transaction) *entity.Transaction {
-	return &entity.Transaction{
+	result := &entity.Transaction{
 		ID: transaction.ID,
 		Status: entity.Status{
 			Name: transaction.Status,
 		},
-		CreatedAt: transaction.CreatedAt,
-		UpdatedAt: transaction.UpdatedAt,
-		Value:     transaction.Value,
+		CreatedAt:      transaction.CreatedAt,
+		UpdatedAt:      transaction.UpdatedAt,
+		MerchantID:     transaction.MerchantID,
+		CustomerID:     transaction.CustomerID,
+		Amount:         transaction.Amount,
+		Currency:       transaction.Currency,
+		Metadata:       transaction.Metadata,
+		Tags:           transaction.Tags,
+		LineItems:      lineItemsFromDTO(transaction.LineItems),
+		RefundedAmount: transaction.RefundedAmount,
+
+		ChargedBackAmount:      transaction.ChargedBackAmount,
+		AuthorizedAmount:       transaction.AuthorizedAmount,
+		CapturedAmount:         transaction.CapturedAmount,
+		VoidedAmount:           transaction.VoidedAmount,
+		AuthorizationExpiresAt: transaction.AuthorizationExpiresAt,
+		SplitType:              transaction.SplitType,
+		ParentID:               transaction.ParentID,
+		Installment:            transaction.Installment,
+		DueAt:                  transaction.DueAt,
+	}
+	if transaction.Breakdown != nil {
+		result.FeeAmount = transaction.Breakdown.Fees
+		result.TaxAmount = transaction.Breakdown.Tax
+		result.NetAmount = transaction.Breakdown.Net
+		result.Charges = chargesFromDTO(transaction.Breakdown.Charges)
+	}
+	if transaction.Settlement != nil {
+		result.SettlementCurrency = transaction.Settlement.Currency
+		result.SettlementAmount = transaction.Settlement.Amount
+		result.FxRate = transaction.Settlement.Rate
+	}
+
+	return result
+}
+
+func breakdownToDTO(transaction *entity.Transaction) *dto.Breakdown {
+	charges := make([]dto.Charge, len(transaction.Charges))
+	for i, charge := range transaction.Charges {
+		charges[i] = dto.Charge(charge)
 	}
+
+	return &dto.Breakdown{
+		Gross:   transaction.Amount,
+		Fees:    transaction.FeeAmount,
+		Tax:     transaction.TaxAmount,
+		Net:     transaction.NetAmount,
+		Charges: charges,
+	}
+}
+
+func settlementToDTO(transaction *entity.Transaction) *dto.Settlement {
+	if transaction.SettlementCurrency == "" {
+		return nil
+	}
+
+	return &dto.Settlement{
+		Currency: transaction.SettlementCurrency,
+		Amount:   transaction.SettlementAmount,
+		Rate:     transaction.FxRate,
+	}
+}
+
+func chargesFromDTO(charges []dto.Charge) entity.Charges {
+	result := make(entity.Charges, len(charges))
+	for i, charge := range charges {
+		result[i] = entity.Charge(charge)
+	}
+
+	return result
+}
+
+func lineItemsToDTO(items []entity.LineItem) []dto.LineItem {
+	if items == nil {
+		return nil
+	}
+
+	dtos := make([]dto.LineItem, len(items))
+	for i, item := range items {
+		dtos[i] = dto.LineItem{
+			SKU:         item.SKU,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Tax:         item.Tax,
+			Discount:    item.Discount,
+			Total:       item.Total,
+		}
+	}
+
+	return dtos
+}
+
+func lineItemsFromDTO(items []dto.LineItem) []entity.LineItem {
+	if items == nil {
+		return nil
+	}
+
+	entities := make([]entity.LineItem, len(items))
+	for i, item := range items {
+		entities[i] = entity.LineItem{
+			Position:    i,
+			SKU:         item.SKU,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Tax:         item.Tax,
+			Discount:    item.Discount,
+			Total:       item.Total,
+		}
+	}
+
+	return entities
 }
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type CustomerRepository struct {
+	postgres database.Postgres
+}
+
+func NewCustomerRepository(postgres database.Postgres) *CustomerRepository {
+	return &CustomerRepository{postgres}
+}
+
+func (r *CustomerRepository) Create(ctx context.Context, customer *entity.Customer) error {
+	return r.postgres.Conn(ctx).Create(customer).Error
+}
+
+func (r *CustomerRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error) {
+	return r.findOne(r.postgres.Conn(ctx), "id = ?", id)
+}
+
+// FindByExternalReference finds the customer holding reference, deleted ones included since they keep
+// their reference.
+func (r *CustomerRepository) FindByExternalReference(ctx context.Context, reference string) (*entity.Customer, error) {
+	return r.findOne(r.postgres.Conn(ctx).Unscoped(), "external_reference = ?", reference)
+}
+
+func (r *CustomerRepository) findOne(db *gorm.DB, query string, args ...any) (*entity.Customer, error) {
+	var customer entity.Customer
+	if err := db.Where(query, args...).First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("customer %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// FindAll returns up to limit customers with IDs greater than after, ordered by ID.
+func (r *CustomerRepository) FindAll(ctx context.Context, after uuid.UUID, limit int) ([]entity.Customer, error) {
+	var customers []entity.Customer
+	err := r.postgres.Conn(ctx).
+		Where("id > ?", after).
+		Order("id").
+		Limit(limit).
+		Find(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (r *CustomerRepository) Update(ctx context.Context, customer *entity.Customer) error {
+	return r.postgres.Conn(ctx).Save(customer).Error
+}
+
+// Delete soft deletes a customer, keeping it referenced by its transactions.
+func (r *CustomerRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.postgres.Conn(ctx).Delete(&entity.Customer{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("customer %w", ErrNotFound)
+	}
+
+	return nil
+}
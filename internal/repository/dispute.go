@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type DisputeRepository struct {
+	postgres database.Postgres
+}
+
+func NewDisputeRepository(postgres database.Postgres) *DisputeRepository {
+	return &DisputeRepository{postgres}
+}
+
+func (r *DisputeRepository) Create(ctx context.Context, dispute *entity.Dispute) error {
+	return r.postgres.Conn(ctx).Create(dispute).Error
+}
+
+// FindByID returns a dispute of the transaction transactionID.
+func (r *DisputeRepository) FindByID(ctx context.Context, transactionID, id uuid.UUID) (*entity.Dispute, error) {
+	var dispute entity.Dispute
+	err := r.postgres.Conn(ctx).First(&dispute, "id = ? AND transaction_id = ?", id, transactionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("dispute %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &dispute, nil
+}
+
+// FindByTransactionID returns the disputes of a transaction, oldest first.
+func (r *DisputeRepository) FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]entity.Dispute, error) {
+	var disputes []entity.Dispute
+	err := r.postgres.Conn(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at, id").
+		Find(&disputes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
+
+func (r *DisputeRepository) Update(ctx context.Context, dispute *entity.Dispute) error {
+	return r.postgres.Conn(ctx).Save(dispute).Error
+}
+
+// SumAmount returns the total amount of the disputes of a transaction in one of statuses.
+func (r *DisputeRepository) SumAmount(ctx context.Context, transactionID uuid.UUID, statuses []string) (int64, error) {
+	var total int64
+	err := r.postgres.Conn(ctx).
+		Model(&entity.Dispute{}).
+		Where("transaction_id = ? AND status IN ?", transactionID, statuses).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// FindOverdue returns up to limit disputes in status whose evidence was due before the given time.
+// Disputes of deleted transactions wait until the transaction is restored.
+func (r *DisputeRepository) FindOverdue(ctx context.Context, status string, before time.Time, limit int) ([]entity.Dispute, error) {
+	db := r.postgres.Conn(ctx)
+
+	var disputes []entity.Dispute
+	err := db.
+		Where("status = ? AND evidence_due_at < ?", status, before).
+		Where("transaction_id IN (?)", db.Model(&entity.Transaction{}).Select("id")).
+		Order("evidence_due_at").
+		Limit(limit).
+		Find(&disputes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
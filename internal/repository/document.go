@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DocumentRepository maintains the Mongo read model of transactions.
+type DocumentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDocumentRepository(mongoDB database.Mongo) *DocumentRepository {
+	return &DocumentRepository{mongoDB.Collection}
+}
+
+// Replace writes the document of transaction, inserting it if needed, unless it already holds a
+// newer version, and reports whether the collection changed. A document of the same version is
+// replaced so that drift is repaired.
+func (r *DocumentRepository) Replace(ctx context.Context, transaction *entity.Transaction) (bool, error) {
+	filter := bson.M{
+		"_id": transaction.ID,
+		"$or": bson.A{
+			bson.M{"version": bson.M{"$lte": transaction.Version}},
+			bson.M{"version": bson.M{"$exists": false}},
+		},
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, filter, transaction, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// The document exists with a newer version.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0 || result.UpsertedCount > 0, nil
+}
+
+func (r *DocumentRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Transaction, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []entity.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// Delete removes the document of id and reports whether there was one.
+func (r *DocumentRepository) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// FindIDs returns up to limit document IDs greater than after, in ascending order.
+func (r *DocumentRepository) FindIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	opts := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$gt": after}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []struct {
+		ID uuid.UUID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(documents))
+	for i := range documents {
+		ids[i] = documents[i].ID
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type HistoryRepository struct {
+	postgres database.Postgres
+}
+
+func NewHistoryRepository(postgres database.Postgres) *HistoryRepository {
+	return &HistoryRepository{postgres}
+}
+
+func (r *HistoryRepository) Create(ctx context.Context, event *entity.TransactionEvent) error {
+	return r.postgres.Conn(ctx).Create(event).Error
+}
+
+// FindByTransactionID returns the history of a transaction, oldest first.
+func (r *HistoryRepository) FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]entity.TransactionEvent, error) {
+	var events []entity.TransactionEvent
+	err := r.postgres.Conn(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at, id").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// FindLast returns the latest change of a transaction recorded as action.
+func (r *HistoryRepository) FindLast(ctx context.Context, transactionID uuid.UUID, action string) (*entity.TransactionEvent, error) {
+	var event entity.TransactionEvent
+	err := r.postgres.Conn(ctx).
+		Where("transaction_id = ? AND action = ?", transactionID, action).
+		Order("created_at DESC, id DESC").
+		First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("transaction event %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(postgres database.Postgres) *IdempotencyRepository {
+	return &IdempotencyRepository{postgres.DB}
+}
+
+// Create inserts key unless it already exists, reporting whether it was inserted.
+func (r *IdempotencyRepository) Create(key *entity.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Reclaim claims key again at now if it is still not completed and was claimed before cutoff,
+// reporting whether it did.
+func (r *IdempotencyRepository) Reclaim(key string, cutoff, now time.Time) (bool, error) {
+	result := r.db.Model(&entity.IdempotencyKey{}).
+		Where("key = ? AND status_code = 0 AND claimed_at < ?", key, cutoff).
+		Update("claimed_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *IdempotencyRepository) FindByKey(key string) (*entity.IdempotencyKey, error) {
+	var idempotencyKey entity.IdempotencyKey
+	if err := r.db.First(&idempotencyKey, "key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("idempotency key %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &idempotencyKey, nil
+}
+
+func (r *IdempotencyRepository) Update(key *entity.IdempotencyKey) error {
+	return r.db.Save(key).Error
+}
+
+func (r *IdempotencyRepository) Delete(key string) error {
+	return r.db.Delete(&entity.IdempotencyKey{}, "key = ?", key).Error
+}
+
+func (r *IdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Delete(&entity.IdempotencyKey{}, "expires_at < ?", now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRunRepository struct {
+	postgres database.Postgres
+}
+
+func NewJobRunRepository(postgres database.Postgres) *JobRunRepository {
+	return &JobRunRepository{postgres}
+}
+
+// Save replaces the last run of the job of run.
+func (r *JobRunRepository) Save(ctx context.Context, run *entity.JobRun) error {
+	return r.postgres.Conn(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(run).Error
+}
+
+func (r *JobRunRepository) FindByName(ctx context.Context, name string) (*entity.JobRun, error) {
+	var run entity.JobRun
+	if err := r.postgres.Conn(ctx).First(&run, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("job run %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
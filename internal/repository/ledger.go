@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountBalance is a ledger account with the sum of its postings.
+type AccountBalance struct {
+	entity.LedgerAccount
+	Balance int64
+}
+
+type LedgerRepository struct {
+	postgres database.Postgres
+}
+
+func NewLedgerRepository(postgres database.Postgres) *LedgerRepository {
+	return &LedgerRepository{postgres}
+}
+
+// FindOrCreateAccount returns the account code in currency, creating it with accountType if needed.
+func (r *LedgerRepository) FindOrCreateAccount(ctx context.Context, code, currency, accountType string) (*entity.LedgerAccount, error) {
+	db := r.postgres.Conn(ctx)
+
+	account := &entity.LedgerAccount{Code: code, Currency: currency, Type: accountType}
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.First(account, "code = ? AND currency = ?", code, currency).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// CreateEntry stores a journal entry with its postings.
+func (r *LedgerRepository) CreateEntry(ctx context.Context, entry *entity.JournalEntry) error {
+	return r.postgres.Conn(ctx).Create(entry).Error
+}
+
+// FindBalances returns every account with its balance, ordered by code and currency.
+func (r *LedgerRepository) FindBalances(ctx context.Context) ([]AccountBalance, error) {
+	db, err := r.balances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []AccountBalance
+	err = db.Order("code, currency").Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+func (r *LedgerRepository) FindBalance(ctx context.Context, accountID uuid.UUID) (*AccountBalance, error) {
+	db, err := r.balances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []AccountBalance
+	err = db.Where("ledger_accounts.id = ?", accountID).Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return nil, fmt.Errorf("ledger account %w", ErrNotFound)
+	}
+
+	return &balances[0], nil
+}
+
+// balances selects the accounts with the sum of their postings.
+func (r *LedgerRepository) balances(ctx context.Context) (*gorm.DB, error) {
+	db := r.postgres.Conn(ctx)
+
+	postingsTable, err := tableName(db, &entity.Posting{})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Model(&entity.LedgerAccount{}).
+		Select("ledger_accounts.*, COALESCE(SUM(postings.amount), 0) AS balance").
+		Joins("LEFT JOIN ? postings ON postings.account_id = ledger_accounts.id", clause.Table{Name: postingsTable}).
+		Group("ledger_accounts.id"), nil
+}
+
+// FindEntries returns the newest journal entries matching query with their postings.
+func (r *LedgerRepository) FindEntries(ctx context.Context, query JournalQuery) ([]entity.JournalEntry, error) {
+	db := r.postgres.Conn(ctx).Preload("Postings")
+	if query.TransactionID != nil {
+		db = db.Where("transaction_id = ?", *query.TransactionID)
+	}
+	if query.AccountID != nil {
+		db = db.Where("id IN (?)", r.postgres.Conn(ctx).
+			Model(&entity.Posting{}).
+			Select("journal_entry_id").
+			Where("account_id = ?", *query.AccountID))
+	}
+
+	var entries []entity.JournalEntry
+	err := db.Order("created_at DESC, id DESC").Limit(query.Limit).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FindUnbalancedEntryIDs returns the journal entries whose postings do not sum to zero.
+func (r *LedgerRepository) FindUnbalancedEntryIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.postgres.Conn(ctx).
+		Model(&entity.Posting{}).
+		Group("journal_entry_id").
+		Having("SUM(amount) <> 0").
+		Pluck("journal_entry_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// SumByCurrency returns the sum of all postings per currency.
+func (r *LedgerRepository) SumByCurrency(ctx context.Context) (map[string]int64, error) {
+	db := r.postgres.Conn(ctx)
+
+	accountsTable, err := tableName(db, &entity.LedgerAccount{})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Currency string
+		Total    int64
+	}
+	err = db.Model(&entity.Posting{}).
+		Select("ledger_accounts.currency, SUM(postings.amount) AS total").
+		Joins("JOIN ? ledger_accounts ON ledger_accounts.id = postings.account_id",
+			clause.Table{Name: accountsTable}).
+		Group("ledger_accounts.currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = row.Total
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LimitRepository struct {
+	postgres database.Postgres
+}
+
+func NewLimitRepository(postgres database.Postgres) *LimitRepository {
+	return &LimitRepository{postgres}
+}
+
+func (r *LimitRepository) Create(ctx context.Context, limit *entity.Limit) error {
+	return r.postgres.Conn(ctx).Create(limit).Error
+}
+
+func (r *LimitRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Limit, error) {
+	var limit entity.Limit
+	if err := r.postgres.Conn(ctx).First(&limit, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("limit %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &limit, nil
+}
+
+func (r *LimitRepository) FindAll(ctx context.Context) ([]entity.Limit, error) {
+	var limits []entity.Limit
+	if err := r.postgres.Conn(ctx).Order("created_at, id").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// FindApplicable returns the enabled limits of scope that apply to subjectID, for operation or,
+// when operation is empty, for every operation.
+func (r *LimitRepository) FindApplicable(
+	ctx context.Context, scope string, subjectID uuid.UUID, operation string) ([]entity.Limit, error) {
+	db := r.postgres.Conn(ctx).
+		Where("disabled = ? AND scope = ?", false, scope).
+		Where("subject_id IS NULL OR subject_id = ?", subjectID)
+	if operation != "" {
+		db = db.Where("operation = ?", operation)
+	}
+
+	var limits []entity.Limit
+	if err := db.Order("created_at, id").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func (r *LimitRepository) Update(ctx context.Context, limit *entity.Limit) error {
+	return r.postgres.Conn(ctx).Save(limit).Error
+}
+
+// Delete removes a limit. Its usage is left to DeleteUsageBefore.
+func (r *LimitRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.postgres.Conn(ctx).Delete(&entity.Limit{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("limit %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// Increment adds the count and amount of usage to the stored usage unless that takes it above
+// maxCount or maxAmount, zero meaning no maximum, and reports whether it did. The usage row stays
+// locked until the surrounding transaction ends, so concurrent increments cannot overshoot.
+func (r *LimitRepository) Increment(ctx context.Context, usage *entity.LimitUsage, maxCount, maxAmount int64) (bool, error) {
+	db := r.postgres.Conn(ctx)
+
+	usageTable, err := tableName(db, &entity.LimitUsage{})
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Exec(`INSERT INTO ? AS u (limit_id, subject_id, period_start, count, amount) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (limit_id, subject_id, period_start) DO UPDATE
+		SET count = u.count + EXCLUDED.count, amount = u.amount + EXCLUDED.amount
+		WHERE (?::bigint = 0 OR u.count + EXCLUDED.count <= ?) AND (?::bigint = 0 OR u.amount + EXCLUDED.amount <= ?)`,
+		clause.Table{Name: usageTable}, usage.LimitID, usage.SubjectID, usage.PeriodStart, usage.Count, usage.Amount,
+		maxCount, maxCount, maxAmount, maxAmount)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// FindUsage returns the usage of a limit by subjectID in the period starting at periodStart,
+// which is zero when nothing was consumed yet.
+func (r *LimitRepository) FindUsage(
+	ctx context.Context, limitID, subjectID uuid.UUID, periodStart time.Time) (*entity.LimitUsage, error) {
+	var usages []entity.LimitUsage
+	err := r.postgres.Conn(ctx).
+		Where("limit_id = ? AND subject_id = ? AND period_start = ?", limitID, subjectID, periodStart).
+		Limit(1).
+		Find(&usages).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(usages) == 0 {
+		return &entity.LimitUsage{LimitID: limitID, SubjectID: subjectID, PeriodStart: periodStart}, nil
+	}
+
+	return &usages[0], nil
+}
+
+// DeleteUsageBefore deletes the usage of periods that started before cutoff.
+func (r *LimitRepository) DeleteUsageBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.postgres.Conn(ctx).Where("period_start < ?", cutoff).Delete(&entity.LimitUsage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type MerchantRepository struct {
+	postgres database.Postgres
+}
+
+func NewMerchantRepository(postgres database.Postgres) *MerchantRepository {
+	return &MerchantRepository{postgres}
+}
+
+func (r *MerchantRepository) Create(ctx context.Context, merchant *entity.Merchant) error {
+	return r.postgres.Conn(ctx).Create(merchant).Error
+}
+
+func (r *MerchantRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Merchant, error) {
+	return r.findOne(r.postgres.Conn(ctx), "id = ?", id)
+}
+
+// FindByExternalReference finds the merchant holding reference, deleted ones included since they keep
+// their reference.
+func (r *MerchantRepository) FindByExternalReference(ctx context.Context, reference string) (*entity.Merchant, error) {
+	return r.findOne(r.postgres.Conn(ctx).Unscoped(), "external_reference = ?", reference)
+}
+
+func (r *MerchantRepository) findOne(db *gorm.DB, query string, args ...any) (*entity.Merchant, error) {
+	var merchant entity.Merchant
+	if err := db.Where(query, args...).First(&merchant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("merchant %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &merchant, nil
+}
+
+// FindAll returns up to limit merchants with IDs greater than after, ordered by ID.
+func (r *MerchantRepository) FindAll(ctx context.Context, after uuid.UUID, limit int) ([]entity.Merchant, error) {
+	var merchants []entity.Merchant
+	err := r.postgres.Conn(ctx).
+		Where("id > ?", after).
+		Order("id").
+		Limit(limit).
+		Find(&merchants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return merchants, nil
+}
+
+func (r *MerchantRepository) Update(ctx context.Context, merchant *entity.Merchant) error {
+	return r.postgres.Conn(ctx).Save(merchant).Error
+}
+
+// Delete soft deletes a merchant, keeping it referenced by its transactions.
+func (r *MerchantRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.postgres.Conn(ctx).Delete(&entity.Merchant{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("merchant %w", ErrNotFound)
+	}
+
+	return nil
+}
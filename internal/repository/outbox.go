@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+type OutboxRepository struct {
+	postgres database.Postgres
+}
+
+func NewOutboxRepository(postgres database.Postgres) *OutboxRepository {
+	return &OutboxRepository{postgres}
+}
+
+func (r *OutboxRepository) Create(ctx context.Context, event *entity.OutboxEvent) error {
+	return r.postgres.Conn(ctx).Create(event).Error
+}
+
+// FindPending returns up to limit pending events due at now in delivery order, skipping those of
+// aggregates whose earlier events still wait for a retry.
+func (r *OutboxRepository) FindPending(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	db := r.postgres.Conn(ctx)
+
+	eventsTable, err := tableName(db, &entity.OutboxEvent{})
+	if err != nil {
+		return nil, err
+	}
+	waiting := db.Table(eventsTable+" AS earlier").
+		Select("1").
+		Where("earlier.aggregate_id = "+eventsTable+".aggregate_id AND earlier.id < "+eventsTable+".id").
+		Where("earlier.processed_at IS NULL AND earlier.dead_at IS NULL AND earlier.next_attempt_at > ?", now)
+
+	var events []entity.OutboxEvent
+	err = db.
+		Where("processed_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", now).
+		Where("NOT EXISTS (?)", waiting).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepository) Update(ctx context.Context, event *entity.OutboxEvent) error {
+	return r.postgres.Conn(ctx).Save(event).Error
+}
+
+// PendingStats returns the number of pending events and the creation time of the oldest one.
+func (r *OutboxRepository) PendingStats(ctx context.Context) (int64, *time.Time, error) {
+	var stats struct {
+		Count  int64
+		Oldest *time.Time
+	}
+	err := r.postgres.Conn(ctx).
+		Model(&entity.OutboxEvent{}).
+		Select("COUNT(*) AS count, MIN(created_at) AS oldest").
+		Where("processed_at IS NULL AND dead_at IS NULL").
+		Scan(&stats).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return stats.Count, stats.Oldest, nil
+}
+
+func (r *OutboxRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.postgres.Conn(ctx).Delete(&entity.OutboxEvent{}, "processed_at < ?", before)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type PricingRepository struct {
+	postgres database.Postgres
+}
+
+func NewPricingRepository(postgres database.Postgres) *PricingRepository {
+	return &PricingRepository{postgres}
+}
+
+func (r *PricingRepository) Create(ctx context.Context, rule *entity.PricingRule) error {
+	return r.postgres.Conn(ctx).Create(rule).Error
+}
+
+func (r *PricingRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.PricingRule, error) {
+	var rule entity.PricingRule
+	if err := r.postgres.Conn(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("pricing rule %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *PricingRepository) FindAll(ctx context.Context) ([]entity.PricingRule, error) {
+	var rules []entity.PricingRule
+	if err := r.postgres.Conn(ctx).Order("created_at, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FindApplicable returns the enabled rules for currency and, when not nil, merchantID, including
+// the rules that apply to every currency or merchant.
+func (r *PricingRepository) FindApplicable(ctx context.Context, currency string, merchantID *uuid.UUID) ([]entity.PricingRule, error) {
+	db := r.postgres.Conn(ctx).
+		Where("disabled = ?", false).
+		Where("currency = '' OR currency = ?", currency)
+	if merchantID != nil {
+		db = db.Where("merchant_id IS NULL OR merchant_id = ?", *merchantID)
+	} else {
+		db = db.Where("merchant_id IS NULL")
+	}
+
+	var rules []entity.PricingRule
+	if err := db.Order("created_at, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *PricingRepository) Update(ctx context.Context, rule *entity.PricingRule) error {
+	return r.postgres.Conn(ctx).Save(rule).Error
+}
+
+// Delete removes a rule. Transactions keep the charges it computed.
+func (r *PricingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.postgres.Conn(ctx).Delete(&entity.PricingRule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("pricing rule %w", ErrNotFound)
+	}
+
+	return nil
+}
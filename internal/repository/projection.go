@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProjectionRepository struct {
+	postgres database.Postgres
+}
+
+func NewProjectionRepository(postgres database.Postgres) *ProjectionRepository {
+	return &ProjectionRepository{postgres}
+}
+
+// Mark queues the given transactions for projection.
+func (r *ProjectionRepository) Mark(ctx context.Context, ids ...uuid.UUID) error {
+	return markForProjection(r.postgres.Conn(ctx), ids...)
+}
+
+// MarkAll queues every transaction, deleted ones included, for projection.
+func (r *ProjectionRepository) MarkAll(ctx context.Context) (int64, error) {
+	db := r.postgres.Conn(ctx)
+
+	tasksTable, err := tableName(db, &entity.ProjectionTask{})
+	if err != nil {
+		return 0, err
+	}
+	transactionsTable, err := tableName(db, &entity.Transaction{})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	result := db.Exec(`INSERT INTO ? (transaction_id, marked_at, attempts, next_attempt_at)
+		SELECT id, ?, 0, ? FROM ?
+		ON CONFLICT (transaction_id) DO UPDATE SET marked_at = EXCLUDED.marked_at, next_attempt_at = EXCLUDED.next_attempt_at`,
+		clause.Table{Name: tasksTable}, now, now, clause.Table{Name: transactionsTable})
+
+	return result.RowsAffected, result.Error
+}
+
+// FindPending returns up to limit tasks that are due, oldest first.
+func (r *ProjectionRepository) FindPending(ctx context.Context, now time.Time, limit int) ([]entity.ProjectionTask, error) {
+	var tasks []entity.ProjectionTask
+	err := r.postgres.Conn(ctx).
+		Where("next_attempt_at <= ?", now).
+		Order("marked_at").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *ProjectionRepository) Update(ctx context.Context, task *entity.ProjectionTask) error {
+	return r.postgres.Conn(ctx).
+		Model(task).
+		Where("marked_at = ?", task.MarkedAt).
+		Updates(map[string]any{
+			"attempts":        task.Attempts,
+			"next_attempt_at": task.NextAttemptAt,
+			"last_error":      task.LastError,
+		}).Error
+}
+
+// Complete removes task unless the transaction was marked again since it was read.
+func (r *ProjectionRepository) Complete(ctx context.Context, task *entity.ProjectionTask) error {
+	return r.postgres.Conn(ctx).
+		Where("transaction_id = ? AND marked_at = ?", task.TransactionID, task.MarkedAt).
+		Delete(&entity.ProjectionTask{}).Error
+}
+
+func (r *ProjectionRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.postgres.Conn(ctx).Model(&entity.ProjectionTask{}).Count(&count).Error
+	return count, err
+}
+
+func markForProjection(db *gorm.DB, ids ...uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	tasks := make([]entity.ProjectionTask, len(ids))
+	for i, id := range ids {
+		tasks[i] = entity.ProjectionTask{TransactionID: id, MarkedAt: now, NextAttemptAt: now}
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"marked_at", "next_attempt_at"}),
+	}).Create(&tasks).Error
+}
+
+// tableName resolves the schema qualified table name of model for raw SQL. Statement.Table drops
+// the schema, so the name is taken from the parsed schema.
+func tableName(db *gorm.DB, model any) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+
+	return stmt.Schema.Table, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionQuery selects a page of transactions ordered by SortField and then by ID.
+type TransactionQuery struct {
+	StatusID       *uuid.UUID
+	MerchantID     *uuid.UUID
+	CustomerID     *uuid.UUID
+	Currency       string
+	MinAmount      *int64
+	MaxAmount      *int64
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	UpdatedFrom    *time.Time
+	UpdatedTo      *time.Time
+	IncludeDeleted bool
+	// Metadata and Tags must be contained in those of the transaction.
+	Metadata map[string]string
+	Tags     []string
+
+	SortField  string
+	Descending bool
+	// After restricts the page to rows strictly after (AfterValue, AfterID) in sort order.
+	After      bool
+	AfterValue any
+	AfterID    uuid.UUID
+
+	Limit int
+}
+
+// CurrencyTotal is the sum and number of the transactions in a currency.
+type CurrencyTotal struct {
+	Currency string
+	Amount   int64
+	Count    int64
+}
+
+// SettlementTotals sums the transactions or entries of a settlement batch.
+type SettlementTotals struct {
+	Count       int64
+	Gross       int64
+	Fees        int64
+	Tax         int64
+	Refunds     int64
+	Chargebacks int64
+}
+
+// SettlementGroup is a merchant and currency with collected transactions waiting for a batch.
+type SettlementGroup struct {
+	MerchantID uuid.UUID
+	Currency   string
+}
+
+// SettlementAdjustment is what a transaction was refunded and charged back beyond its settlement
+// entries.
+type SettlementAdjustment struct {
+	TransactionID        uuid.UUID
+	MerchantID           uuid.UUID
+	Currency             string
+	Status               string
+	TransactionCreatedAt time.Time
+	Refunds              int64
+	Chargebacks          int64
+}
+
+// SettlementBatchQuery selects the newest settlement batches matching its filters.
+type SettlementBatchQuery struct {
+	MerchantID *uuid.UUID
+	Status     string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// ScheduleQuery selects the newest schedules matching its filters.
+type ScheduleQuery struct {
+	MerchantID *uuid.UUID
+	CustomerID *uuid.UUID
+	Status     string
+	Limit      int
+}
+
+// JournalQuery selects the newest journal entries, optionally of one transaction or touching one account.
+type JournalQuery struct {
+	TransactionID *uuid.UUID
+	AccountID     *uuid.UUID
+	Limit         int
+}
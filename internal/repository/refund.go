@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type RefundRepository struct {
+	postgres database.Postgres
+}
+
+func NewRefundRepository(postgres database.Postgres) *RefundRepository {
+	return &RefundRepository{postgres}
+}
+
+func (r *RefundRepository) Create(ctx context.Context, refund *entity.Refund) error {
+	return r.postgres.Conn(ctx).Create(refund).Error
+}
+
+// FindByID returns a refund of the transaction transactionID.
+func (r *RefundRepository) FindByID(ctx context.Context, transactionID, id uuid.UUID) (*entity.Refund, error) {
+	var refund entity.Refund
+	err := r.postgres.Conn(ctx).First(&refund, "id = ? AND transaction_id = ?", id, transactionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("refund %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+// FindByTransactionID returns the refunds of a transaction, oldest first.
+func (r *RefundRepository) FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]entity.Refund, error) {
+	var refunds []entity.Refund
+	err := r.postgres.Conn(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at, id").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+func (r *RefundRepository) Update(ctx context.Context, refund *entity.Refund) error {
+	return r.postgres.Conn(ctx).Save(refund).Error
+}
+
+// SumAmount returns the total amount of the refunds of a transaction in one of statuses.
+func (r *RefundRepository) SumAmount(ctx context.Context, transactionID uuid.UUID, statuses []string) (int64, error) {
+	var total int64
+	err := r.postgres.Conn(ctx).
+		Model(&entity.Refund{}).
+		Where("transaction_id = ? AND status IN ?", transactionID, statuses).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
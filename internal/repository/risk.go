@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type RiskRepository struct {
+	postgres database.Postgres
+}
+
+func NewRiskRepository(postgres database.Postgres) *RiskRepository {
+	return &RiskRepository{postgres}
+}
+
+func (r *RiskRepository) CreateRule(ctx context.Context, rule *entity.RiskRule) error {
+	return r.postgres.Conn(ctx).Create(rule).Error
+}
+
+func (r *RiskRepository) FindRuleByID(ctx context.Context, id uuid.UUID) (*entity.RiskRule, error) {
+	var rule entity.RiskRule
+	if err := r.postgres.Conn(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("risk rule %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// FindRules returns the rules in creation order, only the enabled ones unless includeDisabled.
+func (r *RiskRepository) FindRules(ctx context.Context, includeDisabled bool) ([]entity.RiskRule, error) {
+	db := r.postgres.Conn(ctx)
+	if !includeDisabled {
+		db = db.Where("disabled = ?", false)
+	}
+
+	var rules []entity.RiskRule
+	if err := db.Order("created_at, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *RiskRepository) UpdateRule(ctx context.Context, rule *entity.RiskRule) error {
+	return r.postgres.Conn(ctx).Save(rule).Error
+}
+
+// DeleteRule removes a rule. Assessments keep the hits it produced.
+func (r *RiskRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	result := r.postgres.Conn(ctx).Delete(&entity.RiskRule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("risk rule %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *RiskRepository) CreateAssessment(ctx context.Context, assessment *entity.RiskAssessment) error {
+	return r.postgres.Conn(ctx).Create(assessment).Error
+}
+
+func (r *RiskRepository) FindAssessment(ctx context.Context, transactionID uuid.UUID) (*entity.RiskAssessment, error) {
+	var assessment entity.RiskAssessment
+	if err := r.postgres.Conn(ctx).First(&assessment, "transaction_id = ?", transactionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("risk assessment %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &assessment, nil
+}
+
+func (r *RiskRepository) UpdateAssessment(ctx context.Context, assessment *entity.RiskAssessment) error {
+	return r.postgres.Conn(ctx).Save(assessment).Error
+}
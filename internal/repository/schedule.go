@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduleRepository struct {
+	postgres database.Postgres
+}
+
+func NewScheduleRepository(postgres database.Postgres) *ScheduleRepository {
+	return &ScheduleRepository{postgres}
+}
+
+func (r *ScheduleRepository) Create(ctx context.Context, schedule *entity.Schedule) error {
+	return r.postgres.Conn(ctx).Create(schedule).Error
+}
+
+func (r *ScheduleRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Schedule, error) {
+	return r.findByID(r.postgres.Conn(ctx), id)
+}
+
+// FindByIDForUpdate locks the schedule until the end of the transaction carried by ctx.
+func (r *ScheduleRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Schedule, error) {
+	return r.findByID(r.postgres.Conn(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *ScheduleRepository) findByID(db *gorm.DB, id uuid.UUID) (*entity.Schedule, error) {
+	var schedule entity.Schedule
+	if err := db.First(&schedule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("schedule %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (r *ScheduleRepository) FindAll(ctx context.Context, query ScheduleQuery) ([]entity.Schedule, error) {
+	db := r.postgres.Conn(ctx)
+	if query.MerchantID != nil {
+		db = db.Where("merchant_id = ?", *query.MerchantID)
+	}
+	if query.CustomerID != nil {
+		db = db.Where("customer_id = ?", *query.CustomerID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var schedules []entity.Schedule
+	if err := db.Order("created_at DESC, id").Limit(query.Limit).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (r *ScheduleRepository) Update(ctx context.Context, schedule *entity.Schedule) error {
+	return r.postgres.Conn(ctx).Save(schedule).Error
+}
+
+// FindDue returns the IDs of up to limit schedules in status whose next run is at or before the
+// given time, the most overdue first.
+func (r *ScheduleRepository) FindDue(ctx context.Context, status string, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.postgres.Conn(ctx).
+		Model(&entity.Schedule{}).
+		Where("status = ? AND next_run_at <= ?", status, before).
+		Order("next_run_at, id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/database"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettlementRepository struct {
+	postgres database.Postgres
+}
+
+func NewSettlementRepository(postgres database.Postgres) *SettlementRepository {
+	return &SettlementRepository{postgres}
+}
+
+// FindOrCreate returns the batch of the merchant, currency and date of batch, creating it from
+// batch when there is none.
+func (r *SettlementRepository) FindOrCreate(ctx context.Context, batch *entity.SettlementBatch) (*entity.SettlementBatch, error) {
+	db := r.postgres.Conn(ctx)
+
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(batch).Error; err != nil {
+		return nil, err
+	}
+
+	var existing entity.SettlementBatch
+	err := db.First(&existing, "merchant_id = ? AND currency = ? AND batch_date = ?",
+		batch.MerchantID, batch.Currency, batch.BatchDate).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &existing, nil
+}
+
+func (r *SettlementRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error) {
+	var batch entity.SettlementBatch
+	if err := r.postgres.Conn(ctx).First(&batch, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("settlement batch %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// FindAll returns the batches matching query, newest first.
+func (r *SettlementRepository) FindAll(ctx context.Context, query SettlementBatchQuery) ([]entity.SettlementBatch, error) {
+	db := r.postgres.Conn(ctx)
+	if query.MerchantID != nil {
+		db = db.Where("merchant_id = ?", *query.MerchantID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.From != nil {
+		db = db.Where("batch_date >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("batch_date <= ?", *query.To)
+	}
+
+	var batches []entity.SettlementBatch
+	err := db.Order("batch_date DESC, merchant_id, currency").Limit(query.Limit).Find(&batches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+// FindOpenBefore returns the batches in status whose date is before day.
+func (r *SettlementRepository) FindOpenBefore(ctx context.Context, status string, day time.Time) ([]entity.SettlementBatch, error) {
+	var batches []entity.SettlementBatch
+	err := r.postgres.Conn(ctx).
+		Where("status = ? AND batch_date < ?", status, day).
+		Order("batch_date, id").
+		Find(&batches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+func (r *SettlementRepository) Update(ctx context.Context, batch *entity.SettlementBatch) error {
+	return r.postgres.Conn(ctx).Save(batch).Error
+}
+
+// FindUnbatchedGroups returns the merchants and currencies with transactions in one of statuses
+// that belong to no batch yet.
+func (r *SettlementRepository) FindUnbatchedGroups(ctx context.Context, statuses []string) ([]SettlementGroup, error) {
+	db := r.postgres.Conn(ctx)
+
+	var groups []SettlementGroup
+	err := unbatched(db, statuses).
+		Select("merchant_id, currency").
+		Group("merchant_id, currency").
+		Order("merchant_id, currency").
+		Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// Assign adds the transactions of the merchant and currency of batch that are in one of statuses
+// and belong to no batch yet to batch.
+func (r *SettlementRepository) Assign(ctx context.Context, batch *entity.SettlementBatch, statuses []string) (int64, error) {
+	result := unbatched(r.postgres.Conn(ctx), statuses).
+		Where("merchant_id = ? AND currency = ?", batch.MerchantID, batch.Currency).
+		UpdateColumn("settlement_batch_id", batch.ID)
+
+	return result.RowsAffected, result.Error
+}
+
+// SumTransactions totals the transactions of a batch. The gross amount of an authorized
+// transaction is what was captured.
+func (r *SettlementRepository) SumTransactions(ctx context.Context, batchID uuid.UUID) (*SettlementTotals, error) {
+	var totals SettlementTotals
+	err := r.postgres.Conn(ctx).
+		Model(&entity.Transaction{}).
+		Where("settlement_batch_id = ?", batchID).
+		Select(`COUNT(*) AS count,
+			COALESCE(SUM(CASE WHEN authorized_amount > 0 THEN captured_amount ELSE amount END), 0) AS gross,
+			COALESCE(SUM(fee_amount), 0) AS fees, COALESCE(SUM(tax_amount), 0) AS tax,
+			COALESCE(SUM(refunded_amount), 0) AS refunds, COALESCE(SUM(charged_back_amount), 0) AS chargebacks`).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// FindTransactions returns the transactions of a batch, oldest first.
+func (r *SettlementRepository) FindTransactions(ctx context.Context, batchID uuid.UUID) ([]entity.Transaction, error) {
+	var transactions []entity.Transaction
+	err := r.postgres.Conn(ctx).
+		Preload("Status").
+		Where("settlement_batch_id = ?", batchID).
+		Order("created_at, id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// CreateEntries records the settlement entries.
+func (r *SettlementRepository) CreateEntries(ctx context.Context, entries []entity.SettlementEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return r.postgres.Conn(ctx).Create(&entries).Error
+}
+
+// FindEntries returns the entries of a batch in the order of their transactions.
+func (r *SettlementRepository) FindEntries(ctx context.Context, batchID uuid.UUID) ([]entity.SettlementEntry, error) {
+	var entries []entity.SettlementEntry
+	err := r.postgres.Conn(ctx).
+		Where("batch_id = ?", batchID).
+		Order("transaction_created_at, transaction_id, created_at, id").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// SumEntries totals the entries of a batch. Count is the number of transactions it settled,
+// adjustments left out.
+func (r *SettlementRepository) SumEntries(ctx context.Context, batchID uuid.UUID) (*SettlementTotals, error) {
+	var totals SettlementTotals
+	err := r.postgres.Conn(ctx).
+		Model(&entity.SettlementEntry{}).
+		Where("batch_id = ?", batchID).
+		Select(`COALESCE(SUM(CASE WHEN adjustment THEN 0 ELSE 1 END), 0) AS count,
+			COALESCE(SUM(gross), 0) AS gross, COALESCE(SUM(fees), 0) AS fees, COALESCE(SUM(tax), 0) AS tax,
+			COALESCE(SUM(refunds), 0) AS refunds, COALESCE(SUM(chargebacks), 0) AS chargebacks`).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// FindAdjustments returns the transactions with settlement entries that were refunded or charged
+// back beyond them, that is after their batch closed.
+func (r *SettlementRepository) FindAdjustments(ctx context.Context) ([]SettlementAdjustment, error) {
+	db := r.postgres.Conn(ctx)
+
+	statusesTable, err := tableName(db, &entity.Status{})
+	if err != nil {
+		return nil, err
+	}
+	settled := db.Session(&gorm.Session{NewDB: true}).
+		Model(&entity.SettlementEntry{}).
+		Select("transaction_id, SUM(refunds) AS refunds, SUM(chargebacks) AS chargebacks").
+		Group("transaction_id")
+
+	var adjustments []SettlementAdjustment
+	err = db.Model(&entity.Transaction{}).
+		Select(`transactions.id AS transaction_id, transactions.merchant_id, transactions.currency,
+			statuses.name AS status, transactions.created_at AS transaction_created_at,
+			transactions.refunded_amount - settled.refunds AS refunds,
+			transactions.charged_back_amount - settled.chargebacks AS chargebacks`).
+		Joins("JOIN (?) settled ON settled.transaction_id = transactions.id", settled).
+		Joins("JOIN ? statuses ON statuses.id = transactions.status_id", clause.Table{Name: statusesTable}).
+		Where("transactions.refunded_amount > settled.refunds OR transactions.charged_back_amount > settled.chargebacks").
+		Order("transactions.merchant_id, transactions.currency, transactions.id").
+		Scan(&adjustments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+// unbatched restricts db to the transactions of a merchant in one of statuses without a batch.
+// Split transactions are settled through their children.
+func unbatched(db *gorm.DB, statuses []string) *gorm.DB {
+	return db.Model(&entity.Transaction{}).
+		Where("settlement_batch_id IS NULL AND merchant_id IS NOT NULL AND split_type = ''").
+		Where("status_id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&entity.Status{}).
+			Select("id").Where("name IN ?", statuses))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/the-great-checkout/transactions-crud/internal/database"
@@ -18,7 +19,8 @@ func NewStatusRepository(postgres database.Postgres) *StatusRepository {
 }
 
 func (r *StatusRepository) Create(transaction *entity.Status) error {
-	if err := r.db.Create(transaction).Error; err != nil {
+	// Next only references existing statuses, so only the join rows are written.
+	if err := r.db.Omit("Next.*").Create(transaction).Error; err != nil {
 		return err
 	}
 
@@ -27,9 +29,21 @@ func (r *StatusRepository) Create(transaction *entity.Status) error {
 
 func (r *StatusRepository) FindByID(id uuid.UUID) (*entity.Status, error) {
 	var status entity.Status
-	if err := r.db.First(&status, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Next").First(&status, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("status not found")
+			return nil, fmt.Errorf("status %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+func (r *StatusRepository) FindByName(name string) (*entity.Status, error) {
+	var status entity.Status
+	if err := r.db.Preload("Next").First(&status, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("status %w", ErrNotFound)
 		}
 		return nil, err
 	}
@@ -39,7 +53,7 @@ func (r *StatusRepository) FindByID(id uuid.UUID) (*entity.Status, error) {
 
 func (r *StatusRepository) FindAll() ([]entity.Status, error) {
 	var statuses []entity.Status
-	if err := r.db.Find(&statuses).Error; err != nil {
+	if err := r.db.Preload("Next").Find(&statuses).Error; err != nil {
 		return nil, err
 	}
 	return statuses, nil
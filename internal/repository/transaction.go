@@ -3,44 +3,74 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/the-great-checkout/transactions-crud/internal/database"
 	"github.com/the-great-checkout/transactions-crud/internal/entity"
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// TransactionRepository writes transactions to Postgres. Every write queues the transaction for
+// projection into Mongo in the same database transaction, see ProjectionRepository.
 type TransactionRepository struct {
-	db         *gorm.DB
-	collection *mongo.Collection
+	postgres database.Postgres
 }
 
-func NewTransactionRepository(postgresDB database.Postgres, mongoDB database.Mongo) *TransactionRepository {
-	return &TransactionRepository{postgresDB.DB, mongoDB.Collection}
+func NewTransactionRepository(postgresDB database.Postgres) *TransactionRepository {
+	return &TransactionRepository{postgresDB}
 }
 
-func (r *TransactionRepository) Create(transaction *entity.Transaction) error {
-	var status entity.Status
-	r.db.Where("name = ?", "created").First(&status)
+// orderLineItems preloads line items in the order they were given.
+func orderLineItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func (r *TransactionRepository) Create(ctx context.Context, transaction *entity.Transaction) error {
+	db := r.postgres.Conn(ctx)
 
-	transaction.Status = status
+	if transaction.Status.ID == uuid.Nil {
+		var status entity.Status
+		db.Where("name = ?", "created").First(&status)
+
+		transaction.Status = status
+	}
 
-	if err := r.db.Create(transaction).Error; err != nil {
+	if err := db.Create(transaction).Error; err != nil {
 		return err
 	}
 
-	_, err := r.collection.InsertOne(context.TODO(), transaction)
-	return err
+	return markForProjection(db, transaction.ID)
+}
+
+func (r *TransactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	db := r.postgres.Conn(ctx)
+
+	var transaction entity.Transaction
+	if err := db.Preload("Status").Preload("LineItems", orderLineItems).First(&transaction, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("transaction %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
 }
 
-func (r *TransactionRepository) FindByID(id uuid.UUID) (*entity.Transaction, error) {
+// FindByIDForUpdate is FindByID holding a row lock until the surrounding transaction ends.
+func (r *TransactionRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	db := r.postgres.Conn(ctx)
+
 	var transaction entity.Transaction
-	if err := r.db.Preload("Status").First(&transaction, "id = ?", id).Error; err != nil {
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+		Preload("Status").
+		Preload("LineItems", orderLineItems).
+		First(&transaction, "id = ?", id).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("transaction not found")
+			return nil, fmt.Errorf("transaction %w", ErrNotFound)
 		}
 		return nil, err
 	}
@@ -48,51 +78,163 @@ func (r *TransactionRepository) FindByID(id uuid.UUID) (*entity.Transaction, err
 	return &transaction, nil
 }
 
-func (r *TransactionRepository) FindAll() ([]entity.Transaction, error) {
+func (r *TransactionRepository) FindAll(ctx context.Context, query TransactionQuery) ([]entity.Transaction, error) {
+	db := filterTransactions(r.postgres.Conn(ctx).Preload("Status"), &query)
+
+	sortColumn := clause.Column{Name: query.SortField}
+	if query.After {
+		operator := ">"
+		if query.Descending {
+			operator = "<"
+		}
+		db = db.Where(clause.Expr{
+			SQL:  "(?, id) " + operator + " (?, ?)",
+			Vars: []any{sortColumn, query.AfterValue, query.AfterID},
+		})
+	}
+
 	var transactions []entity.Transaction
-	if err := r.db.Preload("Status").Find(&transactions).Error; err != nil {
+	err := db.
+		Order(clause.OrderByColumn{Column: sortColumn, Desc: query.Descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: query.Descending}).
+		Limit(query.Limit).
+		Find(&transactions).Error
+	if err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
 
-func (r *TransactionRepository) Update(transaction *entity.Transaction) error {
+// SumByCurrency totals the amounts of the transactions selected by the filters of query, ignoring
+// its sort and paging. Split transactions are counted through their children.
+func (r *TransactionRepository) SumByCurrency(ctx context.Context, query TransactionQuery) ([]CurrencyTotal, error) {
+	db := filterTransactions(r.postgres.Conn(ctx).Model(&entity.Transaction{}), &query).Where("split_type = ''")
+
+	var totals []CurrencyTotal
+	err := db.
+		Select("currency, SUM(amount) AS amount, COUNT(*) AS count").
+		Group("currency").
+		Order("currency").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// CountByCustomerSince counts the transactions of a customer created since a time, deleted ones
+// included. The children of split transactions are not counted.
+func (r *TransactionRepository) CountByCustomerSince(ctx context.Context, customerID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.postgres.Conn(ctx).Unscoped().Model(&entity.Transaction{}).
+		Where("customer_id = ? AND created_at >= ? AND parent_id IS NULL", customerID, since).
+		Count(&count).Error
+
+	return count, err
+}
+
+// filterTransactions restricts db to the transactions matching the filters of query.
+func filterTransactions(db *gorm.DB, query *TransactionQuery) *gorm.DB {
+	if query.IncludeDeleted {
+		db = db.Unscoped()
+	}
+
+	if query.StatusID != nil {
+		db = db.Where("status_id = ?", *query.StatusID)
+	}
+	if query.MerchantID != nil {
+		db = db.Where("merchant_id = ?", *query.MerchantID)
+	}
+	if query.CustomerID != nil {
+		db = db.Where("customer_id = ?", *query.CustomerID)
+	}
+	if query.Currency != "" {
+		db = db.Where("currency = ?", query.Currency)
+	}
+	if query.MinAmount != nil {
+		db = db.Where("amount >= ?", *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		db = db.Where("amount <= ?", *query.MaxAmount)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		db = db.Where("created_at < ?", *query.CreatedTo)
+	}
+	if query.UpdatedFrom != nil {
+		db = db.Where("updated_at >= ?", *query.UpdatedFrom)
+	}
+	if query.UpdatedTo != nil {
+		db = db.Where("updated_at < ?", *query.UpdatedTo)
+	}
+
+	if len(query.Metadata) > 0 {
+		db = db.Where("metadata @> ?::jsonb", entity.Metadata(query.Metadata))
+	}
+	if len(query.Tags) > 0 {
+		db = db.Where("tags @> ?::jsonb", entity.Tags(query.Tags))
+	}
+
+	return db
+}
+
+func (r *TransactionRepository) Update(ctx context.Context, transaction *entity.Transaction) error {
+	db := r.postgres.Conn(ctx)
+
 	existingTransaction := &entity.Transaction{}
-	if err := r.db.First(existingTransaction, "id = ?", transaction.ID).Error; err != nil {
+	if err := db.First(existingTransaction, "id = ?", transaction.ID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("transaction not found")
+			return fmt.Errorf("transaction %w", ErrNotFound)
 		}
 		return err
 	}
 
-	var status entity.Status
-	r.db.Where("name = ?", transaction.Status.Name).First(&status)
-
-	existingTransaction.Status = status
-	existingTransaction.Value = transaction.Value
+	existingTransaction.StatusID = transaction.Status.ID
+	existingTransaction.Status = transaction.Status
+	existingTransaction.Amount = transaction.Amount
+	existingTransaction.Metadata = transaction.Metadata
+	existingTransaction.Tags = transaction.Tags
+	existingTransaction.RefundedAmount = transaction.RefundedAmount
+	existingTransaction.ChargedBackAmount = transaction.ChargedBackAmount
+	existingTransaction.AuthorizedAmount = transaction.AuthorizedAmount
+	existingTransaction.CapturedAmount = transaction.CapturedAmount
+	existingTransaction.VoidedAmount = transaction.VoidedAmount
+	existingTransaction.AuthorizationExpiresAt = transaction.AuthorizationExpiresAt
+	existingTransaction.FeeAmount = transaction.FeeAmount
+	existingTransaction.TaxAmount = transaction.TaxAmount
+	existingTransaction.NetAmount = transaction.NetAmount
+	existingTransaction.Charges = transaction.Charges
+	existingTransaction.SettlementCurrency = transaction.SettlementCurrency
+	existingTransaction.SettlementAmount = transaction.SettlementAmount
+	existingTransaction.FxRate = transaction.FxRate
+	existingTransaction.SplitType = transaction.SplitType
+	existingTransaction.UpdatedAt = transaction.UpdatedAt
+	existingTransaction.Version++
 
-	if err := r.db.Save(existingTransaction).Error; err != nil {
+	if err := db.Omit("Status").Save(existingTransaction).Error; err != nil {
 		return err
 	}
+	transaction.Version = existingTransaction.Version
 
-	filter := bson.M{"_id": existingTransaction}
-
-	update := bson.M{"$set": existingTransaction}
-
-	_, err := r.collection.UpdateOne(context.TODO(), filter, update)
-	return err
+	return markForProjection(db, transaction.ID)
 }
 
-func (r *TransactionRepository) Delete(id uuid.UUID) (*entity.Transaction, error) {
+func (r *TransactionRepository) Delete(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	db := r.postgres.Conn(ctx)
+
 	var status entity.Status
-	r.db.Where("name = ?", "deleted").First(&status)
+	db.Where("name = ?", "deleted").First(&status)
 
 	// Manually update the is_deleted and deleted_at fields
 	var transaction entity.Transaction
-	result := r.db.Model(&transaction).Where("id = ?", id).Updates(map[string]any{
+	result := db.Model(&transaction).Where("id = ?", id).Updates(map[string]any{
 		"is_deleted": true,
 		"deleted_at": time.Now(), // Set current time for deleted_at
-		"status":     status,
+		"status_id":  status.ID,
+		"version":    gorm.Expr("version + 1"),
 	})
 
 	if result.Error != nil {
@@ -104,10 +246,210 @@ func (r *TransactionRepository) Delete(id uuid.UUID) (*entity.Transaction, error
 	}
 
 	var updatedTransaction entity.Transaction
-	err := r.db.Unscoped().Where("id = ?", id).First(&updatedTransaction).Error
+	err := db.Unscoped().Preload("Status").Preload("LineItems", orderLineItems).Where("id = ?", id).First(&updatedTransaction).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedTransaction, markForProjection(db, id)
+}
+
+// FindChildren returns the children of a split transaction, deleted ones included, in installment
+// and creation order.
+func (r *TransactionRepository) FindChildren(ctx context.Context, parentID uuid.UUID) ([]entity.Transaction, error) {
+	var children []entity.Transaction
+	err := r.postgres.Conn(ctx).
+		Unscoped().
+		Preload("Status").
+		Where("parent_id = ?", parentID).
+		Order("installment, created_at, id").
+		Find(&children).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// FindExpiredAuthorizations returns up to limit transactions with an authorization that expired
+// before the given time and still has an uncaptured remainder.
+func (r *TransactionRepository) FindExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.postgres.Conn(ctx).
+		Model(&entity.Transaction{}).
+		Where("authorization_expires_at < ?", before).
+		Where("authorized_amount > captured_amount + voided_amount").
+		Order("authorization_expires_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Restore undoes the soft deletion of a transaction, moving it to status.
+func (r *TransactionRepository) Restore(ctx context.Context, id uuid.UUID, status *entity.Status) (*entity.Transaction, error) {
+	db := r.postgres.Conn(ctx)
+
+	result := db.Unscoped().Model(&entity.Transaction{}).Where("id = ? AND deleted_at IS NOT NULL", id).Updates(map[string]any{
+		"is_deleted": false,
+		"deleted_at": nil,
+		"status_id":  status.ID,
+		"updated_at": time.Now(),
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("deleted transaction %w", ErrNotFound)
+	}
+
+	transaction, err := r.FindByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, markForProjection(db, id)
+}
+
+// FindDeletedBefore returns up to limit purgeable transactions soft deleted before the given time,
+// the oldest deletions first.
+func (r *TransactionRepository) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	db := r.postgres.Conn(ctx)
+
+	var ids []uuid.UUID
+	err := purgeable(db.Unscoped().Model(&entity.Transaction{}), db, before).
+		Order("deleted_at, id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Purge permanently deletes a transaction soft deleted before the given time, with its line items,
+// refunds, disputes, risk assessment and history, and returns what it deleted. It returns nil when
+// the transaction is not deleted, was deleted later or is not purgeable. Journal entries are kept so
+// the ledger still balances. Call it inside a transaction.
+func (r *TransactionRepository) Purge(ctx context.Context, id uuid.UUID, before time.Time) (*entity.Transaction, error) {
+	db := r.postgres.Conn(ctx)
+
+	var transaction entity.Transaction
+	err := purgeable(db.Unscoped(), db, before).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+		Preload("Status").
+		Preload("LineItems", orderLineItems).
+		First(&transaction, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Lets the history trigger accept the deletes until the end of the transaction.
+	if err = db.Exec("SELECT set_config('transactions.purge', 'on', true)").Error; err != nil {
+		return nil, err
+	}
+	owned := []any{&entity.LineItem{}, &entity.Refund{}, &entity.Dispute{}, &entity.RiskAssessment{}, &entity.TransactionEvent{}}
+	for _, model := range owned {
+		if err = db.Where("transaction_id = ?", id).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err = db.Unscoped().Delete(&entity.Transaction{}, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &transaction, markForProjection(db, id)
+}
+
+// purgeable restricts query to the transactions deleted before the given time that can be purged.
+// Transactions that were settled stay, as their settlement batches and entries record the payout,
+// and so do split transactions until their children, which point to them, are purged.
+func purgeable(query, db *gorm.DB, before time.Time) *gorm.DB {
+	newDB := func() *gorm.DB { return db.Session(&gorm.Session{NewDB: true}) }
+
+	return query.
+		Where("deleted_at < ? AND settlement_batch_id IS NULL", before).
+		Where("id NOT IN (?)", newDB().Model(&entity.SettlementEntry{}).Select("transaction_id")).
+		Where("id NOT IN (?)", newDB().Unscoped().Model(&entity.Transaction{}).
+			Select("parent_id").Where("parent_id IS NOT NULL"))
+}
+
+// FindStale returns up to limit transactions in one of statuses that were last updated before the
+// given time, the stalest first.
+func (r *TransactionRepository) FindStale(
+	ctx context.Context, statuses []string, before time.Time, limit int) ([]uuid.UUID, error) {
+	db := r.postgres.Conn(ctx)
+
+	var ids []uuid.UUID
+	err := db.Model(&entity.Transaction{}).
+		Where("status_id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&entity.Status{}).
+			Select("id").Where("name IN ?", statuses)).
+		Where("updated_at < ?", before).
+		Order("updated_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// FindByIDWithDeleted is FindByID including soft deleted transactions.
+func (r *TransactionRepository) FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+	err := r.postgres.Conn(ctx).
+		Unscoped().
+		Preload("Status").
+		Preload("LineItems", orderLineItems).
+		First(&transaction, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("transaction %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// FindBatch returns up to limit transactions, deleted ones included, with IDs greater than after.
+func (r *TransactionRepository) FindBatch(ctx context.Context, after uuid.UUID, limit int) ([]entity.Transaction, error) {
+	var transactions []entity.Transaction
+	err := r.postgres.Conn(ctx).
+		Unscoped().
+		Preload("Status").
+		Preload("LineItems", orderLineItems).
+		Where("id > ?", after).
+		Order("id").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// FindExistingIDs returns which of ids exist, deleted transactions included.
+func (r *TransactionRepository) FindExistingIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var existing []uuid.UUID
+	err := r.postgres.Conn(ctx).
+		Unscoped().
+		Model(&entity.Transaction{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existing).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return &updatedTransaction, nil
+	return existing, nil
 }
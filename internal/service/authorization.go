@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+const (
+	// authorizationLockKey is the advisory lock held by the replica expiring authorizations.
+	authorizationLockKey   = 5_000_003
+	authorizationBatchSize = 100
+
+	TransactionAuthorizedEvent           = "transaction.authorized"
+	TransactionCapturedEvent             = "transaction.captured"
+	TransactionVoidedEvent               = "transaction.voided"
+	TransactionAuthorizationExpiredEvent = "transaction.authorization_expired"
+)
+
+// errAuthorizationClosed reports an expiry candidate that was captured or voided in the meantime.
+var errAuthorizationClosed = errors.New("authorization is closed")
+
+type AuthorizationRepository interface {
+	FindExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// AuthorizationTransactions changes locked transactions. It is implemented by TransactionService.
+type AuthorizationTransactions interface {
+	change(
+		ctx context.Context, id uuid.UUID, eventType, action string, fn func(*entity.Transaction) (string, error),
+	) (*dto.Transaction, error)
+}
+
+// AuthorizationService holds, captures and releases the amount of transactions.
+type AuthorizationService struct {
+	repository   AuthorizationRepository
+	transactions AuthorizationTransactions
+	transactor   Transactor
+	locker       Locker
+	limiter      Limiter
+	ttl          time.Duration
+}
+
+func NewAuthorizationService(
+	repository AuthorizationRepository, transactions AuthorizationTransactions, transactor Transactor, locker Locker,
+	limiter Limiter, ttl time.Duration) *AuthorizationService {
+	return &AuthorizationService{
+		repository:   repository,
+		transactions: transactions,
+		transactor:   transactor,
+		locker:       locker,
+		limiter:      limiter,
+		ttl:          ttl,
+	}
+}
+
+// Authorize holds the whole amount of a created or pending transaction until it is captured,
+// voided or expires after the configured TTL.
+func (s *AuthorizationService) Authorize(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	authorize := func(transaction *entity.Transaction) (string, error) {
+		if transaction.AuthorizedAmount > 0 {
+			return "", fmt.Errorf("%w: transaction is already authorized", ErrInvalidTransition)
+		}
+		if transaction.Amount <= 0 {
+			return "", fmt.Errorf("%w: only a positive amount can be authorized", ErrInvalidInput)
+		}
+
+		expiresAt := time.Now().Add(s.ttl)
+		transaction.AuthorizedAmount = transaction.Amount
+		transaction.AuthorizationExpiresAt = &expiresAt
+
+		return authorizedStatus, nil
+	}
+
+	return s.transactions.change(ctx, id, TransactionAuthorizedEvent, HistoryAuthorized, authorize)
+}
+
+// Capture captures amount of the open authorization, or all of it when amount is zero. The
+// transaction stays partially_captured until nothing remains to capture.
+func (s *AuthorizationService) Capture(ctx context.Context, id uuid.UUID, amount int64) (*dto.Transaction, error) {
+	var transaction *dto.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.transactions.change(ctx, id, TransactionCapturedEvent, HistoryCaptured, s.capture(ctx, amount))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// capture returns the change capturing amount, counting it against the capture limits within the
+// transaction of ctx.
+func (s *AuthorizationService) capture(ctx context.Context, amount int64) func(*entity.Transaction) (string, error) {
+	return func(transaction *entity.Transaction) (string, error) {
+		remaining, err := openAuthorization(transaction)
+		if err != nil {
+			return "", err
+		}
+		if transaction.AuthorizationExpiresAt.Before(time.Now()) {
+			return "", ErrAuthorizationExpired
+		}
+
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount < 0 {
+			return "", fmt.Errorf("%w: amount must be positive", ErrInvalidInput)
+		}
+		if amount > remaining {
+			return "", fmt.Errorf("%w: %d remains to capture", ErrCaptureExceedsAuthorization, remaining)
+		}
+		if err = s.limiter.Consume(ctx, LimitCapture, transaction, amount); err != nil {
+			return "", err
+		}
+		transaction.CapturedAmount += amount
+
+		if amount == remaining {
+			return completedStatus, nil
+		}
+		return partiallyCapturedStatus, nil
+	}
+}
+
+// Void releases the uncaptured remainder of the authorization.
+func (s *AuthorizationService) Void(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	return s.transactions.change(ctx, id, TransactionVoidedEvent, HistoryVoided, voidRemainder)
+}
+
+// ExpireAuthorizations voids the remainder of every expired authorization. Only one replica
+// expires at a time.
+func (s *AuthorizationService) ExpireAuthorizations(ctx context.Context) error {
+	for {
+		expired := 0
+		_, err := s.locker.WithAdvisoryLock(ctx, authorizationLockKey, func(ctx context.Context) error {
+			ids, err := s.repository.FindExpiredAuthorizations(ctx, time.Now(), authorizationBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				_, err = s.transactions.change(ctx, id, TransactionAuthorizationExpiredEvent, HistoryExpired, expireRemainder)
+				if err != nil && !errors.Is(err, errAuthorizationClosed) {
+					return err
+				}
+			}
+			expired = len(ids)
+
+			return nil
+		})
+		if err != nil || expired < authorizationBatchSize {
+			return err
+		}
+	}
+}
+
+// voidRemainder releases what is left of the authorization. The transaction is voided when nothing
+// was captured and completed otherwise.
+func voidRemainder(transaction *entity.Transaction) (string, error) {
+	remaining, err := openAuthorization(transaction)
+	if err != nil {
+		return "", err
+	}
+	transaction.VoidedAmount += remaining
+
+	if transaction.CapturedAmount == 0 {
+		return voidedStatus, nil
+	}
+	return completedStatus, nil
+}
+
+func expireRemainder(transaction *entity.Transaction) (string, error) {
+	if transaction.AuthorizedAmount <= transaction.CapturedAmount+transaction.VoidedAmount ||
+		transaction.AuthorizationExpiresAt == nil || transaction.AuthorizationExpiresAt.After(time.Now()) {
+		return "", errAuthorizationClosed
+	}
+
+	return voidRemainder(transaction)
+}
+
+// openAuthorization returns the amount of the authorization that is neither captured nor voided.
+func openAuthorization(transaction *entity.Transaction) (int64, error) {
+	remaining := transaction.AuthorizedAmount - transaction.CapturedAmount - transaction.VoidedAmount
+	if remaining <= 0 {
+		return 0, fmt.Errorf("%w: transaction has no open authorization", ErrInvalidTransition)
+	}
+
+	return remaining, nil
+}
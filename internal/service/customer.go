@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+type CustomerRepository interface {
+	Create(ctx context.Context, customer *entity.Customer) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error)
+	FindByExternalReference(ctx context.Context, reference string) (*entity.Customer, error)
+	FindAll(ctx context.Context, after uuid.UUID, limit int) ([]entity.Customer, error)
+	Update(ctx context.Context, customer *entity.Customer) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type CustomerMapper interface {
+	ToDTO(customer *entity.Customer) *dto.Customer
+	FromDTO(customer *dto.Customer) *entity.Customer
+}
+
+type CustomerService struct {
+	repository CustomerRepository
+	mapper     CustomerMapper
+}
+
+func NewCustomerService(repository CustomerRepository, mapper CustomerMapper) *CustomerService {
+	return &CustomerService{repository: repository, mapper: mapper}
+}
+
+func (s *CustomerService) Create(ctx context.Context, input *dto.Customer) (*dto.Customer, error) {
+	if err := validateParty(input.Name, input.Email); err != nil {
+		return nil, err
+	}
+	if err := s.checkReference(ctx, uuid.Nil, input.ExternalReference); err != nil {
+		return nil, err
+	}
+
+	customer := s.mapper.FromDTO(input)
+	customer.ID = uuid.Nil
+	if err := s.repository.Create(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(customer), nil
+}
+
+func (s *CustomerService) GetByID(ctx context.Context, id uuid.UUID) (*dto.Customer, error) {
+	customer, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(customer), nil
+}
+
+// GetAll returns a page of customers ordered by ID, starting after filter.After.
+func (s *CustomerService) GetAll(ctx context.Context, filter dto.PageFilter) ([]dto.Customer, error) {
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	customers, err := s.repository.FindAll(ctx, filter.After, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Customer, len(customers))
+	for i := range customers {
+		dtos[i] = *s.mapper.ToDTO(&customers[i])
+	}
+
+	return dtos, nil
+}
+
+func (s *CustomerService) Update(ctx context.Context, id uuid.UUID, input *dto.Customer) (*dto.Customer, error) {
+	if err := validateParty(input.Name, input.Email); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkReference(ctx, id, input.ExternalReference); err != nil {
+		return nil, err
+	}
+
+	customer.Name = input.Name
+	customer.ExternalReference = input.ExternalReference
+	customer.Email = input.Email
+	if err = s.repository.Update(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(customer), nil
+}
+
+func (s *CustomerService) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.repository.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCustomerNotFound
+	}
+
+	return err
+}
+
+func (s *CustomerService) findByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error) {
+	customer, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+
+	return customer, err
+}
+
+// checkReference rejects an external reference already used by a customer other than id, even a
+// deleted one.
+func (s *CustomerService) checkReference(ctx context.Context, id uuid.UUID, reference string) error {
+	if reference == "" {
+		return nil
+	}
+
+	existing, err := s.repository.FindByExternalReference(ctx, reference)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return fmt.Errorf("%w: customer %q", ErrDuplicateReference, reference)
+	}
+
+	return nil
+}
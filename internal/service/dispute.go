@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	DisputeOpened            = "opened"
+	DisputeEvidenceSubmitted = "evidence_submitted"
+	DisputeWon               = "won"
+	DisputeLost              = "lost"
+
+	DisputeOpenedEvent            = "dispute.opened"
+	DisputeEvidenceSubmittedEvent = "dispute.evidence_submitted"
+	DisputeWonEvent               = "dispute.won"
+	DisputeLostEvent              = "dispute.lost"
+
+	TransactionChargedBackEvent = "transaction.charged_back"
+
+	disputeLockKey   = 5_000_004
+	disputeBatchSize = 100
+
+	maxEvidenceFiles = 20
+)
+
+// disputeTransitions is the dispute status graph. Evidence can be added until the dispute is won
+// or lost, which is final.
+var disputeTransitions = map[string][]string{
+	DisputeOpened:            {DisputeEvidenceSubmitted, DisputeLost},
+	DisputeEvidenceSubmitted: {DisputeEvidenceSubmitted, DisputeWon, DisputeLost},
+	DisputeWon:               nil,
+	DisputeLost:              nil,
+}
+
+// openDisputeStatuses are the statuses of the disputes that are not resolved yet.
+var openDisputeStatuses = []string{DisputeOpened, DisputeEvidenceSubmitted}
+
+// errDisputeClosed reports an expiry candidate that received evidence in the meantime.
+var errDisputeClosed = errors.New("dispute is closed")
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *entity.Dispute) error
+	FindByID(ctx context.Context, transactionID, id uuid.UUID) (*entity.Dispute, error)
+	FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]entity.Dispute, error)
+	Update(ctx context.Context, dispute *entity.Dispute) error
+	SumAmount(ctx context.Context, transactionID uuid.UUID, statuses []string) (int64, error)
+	FindOverdue(ctx context.Context, status string, before time.Time, limit int) ([]entity.Dispute, error)
+}
+
+type DisputeMapper interface {
+	ToDTO(dispute *entity.Dispute) *dto.Dispute
+	EvidenceFromDTO(files []dto.EvidenceFile) entity.Evidence
+}
+
+// ClaimedAmounts sums the amounts of the refunds or disputes of a transaction in one of statuses.
+type ClaimedAmounts interface {
+	SumAmount(ctx context.Context, transactionID uuid.UUID, statuses []string) (int64, error)
+}
+
+// DisputeTransactions loads and changes the transaction a dispute belongs to. It is implemented by
+// TransactionService.
+type DisputeTransactions interface {
+	findByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	findByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	change(
+		ctx context.Context, id uuid.UUID, eventType, action string, fn func(*entity.Transaction) (string, error),
+	) (*dto.Transaction, error)
+}
+
+// DisputeService tracks the chargebacks raised against collected transactions.
+type DisputeService struct {
+	transactor     Transactor
+	locker         Locker
+	repository     DisputeRepository
+	refunds        ClaimedAmounts
+	transactions   DisputeTransactions
+	outbox         Outbox
+	mapper         DisputeMapper
+	evidenceWindow time.Duration
+}
+
+func NewDisputeService(
+	transactor Transactor,
+	locker Locker,
+	repository DisputeRepository,
+	refunds ClaimedAmounts,
+	transactions DisputeTransactions,
+	outbox Outbox,
+	mapper DisputeMapper,
+	evidenceWindow time.Duration) *DisputeService {
+	return &DisputeService{
+		transactor:     transactor,
+		locker:         locker,
+		repository:     repository,
+		refunds:        refunds,
+		transactions:   transactions,
+		outbox:         outbox,
+		mapper:         mapper,
+		evidenceWindow: evidenceWindow,
+	}
+}
+
+// Open records a dispute of part or all of a completed or partially refunded transaction. Open
+// disputes and pending or succeeded refunds together may not exceed the collected amount.
+func (s *DisputeService) Open(ctx context.Context, transactionID uuid.UUID, input *dto.Dispute) (*dto.Dispute, error) {
+	var dispute *entity.Dispute
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		transaction, err := s.transactions.findByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		dueAt, err := s.checkOpen(transaction, input)
+		if err != nil {
+			return err
+		}
+
+		available, err := unclaimedAmount(ctx, transaction, s.refunds, s.repository)
+		if err != nil {
+			return err
+		}
+		if input.Amount > available {
+			return fmt.Errorf("%w: only %d is neither refunded nor disputed", ErrDisputeExceedsAmount, available)
+		}
+
+		dispute = &entity.Dispute{
+			TransactionID: transactionID,
+			Amount:        input.Amount,
+			Currency:      transaction.Currency,
+			Status:        DisputeOpened,
+			Reason:        input.Reason,
+			EvidenceDueAt: dueAt,
+			Evidence:      entity.Evidence{},
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		if err = s.repository.Create(ctx, dispute); err != nil {
+			return err
+		}
+
+		return s.outbox.Enqueue(ctx, transactionID, DisputeOpenedEvent, s.mapper.ToDTO(dispute))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(dispute), nil
+}
+
+// checkOpen validates a new dispute of transaction and returns its evidence deadline.
+func (s *DisputeService) checkOpen(transaction *entity.Transaction, input *dto.Dispute) (time.Time, error) {
+	if !refundableStatuses[transaction.Status.Name] {
+		return time.Time{}, fmt.Errorf("%w: transaction is %q", ErrNotDisputable, transaction.Status.Name)
+	}
+	if transaction.SplitType != "" {
+		return time.Time{}, fmt.Errorf("%w: dispute the children of a split transaction", ErrNotDisputable)
+	}
+	currencyCode := currency.Normalize(input.Currency)
+	if currencyCode != "" && currencyCode != transaction.Currency {
+		return time.Time{}, fmt.Errorf("%w: dispute currency must be %s", ErrInvalidInput, transaction.Currency)
+	}
+	if input.Amount <= 0 {
+		return time.Time{}, fmt.Errorf("%w: amount must be positive", ErrInvalidInput)
+	}
+
+	if input.EvidenceDueAt.IsZero() {
+		return time.Now().Add(s.evidenceWindow), nil
+	}
+	if !input.EvidenceDueAt.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("%w: evidence_due_at must be in the future", ErrInvalidInput)
+	}
+
+	return input.EvidenceDueAt, nil
+}
+
+// SubmitEvidence attaches files to a dispute before its evidence deadline and moves it to
+// evidence_submitted.
+func (s *DisputeService) SubmitEvidence(
+	ctx context.Context, transactionID, id uuid.UUID, files []dto.EvidenceFile) (*dto.Dispute, error) {
+	if err := validateEvidence(files); err != nil {
+		return nil, err
+	}
+
+	var dispute *entity.Dispute
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.transactions.findByIDForUpdate(ctx, transactionID); err != nil {
+			return err
+		}
+
+		var err error
+		dispute, err = s.findByID(ctx, transactionID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if now.After(dispute.EvidenceDueAt) {
+			return fmt.Errorf("%w: evidence was due at %s", ErrEvidenceDeadlinePassed, dispute.EvidenceDueAt.Format(time.RFC3339))
+		}
+		if len(dispute.Evidence)+len(files) > maxEvidenceFiles {
+			return fmt.Errorf("%w: a dispute holds at most %d evidence files", ErrInvalidInput, maxEvidenceFiles)
+		}
+		if err = transitionDispute(dispute, DisputeEvidenceSubmitted); err != nil {
+			return err
+		}
+
+		evidence := s.mapper.EvidenceFromDTO(files)
+		for i := range evidence {
+			evidence[i].SubmittedAt = now
+		}
+		dispute.Evidence = append(dispute.Evidence, evidence...)
+		dispute.UpdatedAt = now
+
+		if err = s.repository.Update(ctx, dispute); err != nil {
+			return err
+		}
+
+		return s.outbox.Enqueue(ctx, transactionID, DisputeEvidenceSubmittedEvent, s.mapper.ToDTO(dispute))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(dispute), nil
+}
+
+// UpdateStatus resolves a dispute as won or lost. A lost dispute charges its amount back, which
+// moves it from cash to chargebacks in the ledger.
+func (s *DisputeService) UpdateStatus(ctx context.Context, transactionID, id uuid.UUID, status string) (*dto.Dispute, error) {
+	if status == DisputeOpened || status == DisputeEvidenceSubmitted {
+		return nil, fmt.Errorf("%w: %q is set by its own operation", ErrInvalidTransition, status)
+	}
+
+	dispute, err := s.resolve(ctx, transactionID, id, status, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(dispute), nil
+}
+
+// ExpireDisputes loses every dispute still opened after its evidence deadline. Only one replica
+// expires at a time.
+func (s *DisputeService) ExpireDisputes(ctx context.Context) error {
+	for {
+		expired := 0
+		_, err := s.locker.WithAdvisoryLock(ctx, disputeLockKey, func(ctx context.Context) error {
+			disputes, err := s.repository.FindOverdue(ctx, DisputeOpened, time.Now(), disputeBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for i := range disputes {
+				_, err = s.resolve(ctx, disputes[i].TransactionID, disputes[i].ID, DisputeLost, overdue)
+				if err != nil && !errors.Is(err, errDisputeClosed) && !errors.Is(err, ErrTransactionNotFound) {
+					return err
+				}
+			}
+			expired = len(disputes)
+
+			return nil
+		})
+		if err != nil || expired < disputeBatchSize {
+			return err
+		}
+	}
+}
+
+// resolve moves a dispute to won or lost once check, when given, accepts it.
+func (s *DisputeService) resolve(
+	ctx context.Context, transactionID, id uuid.UUID, status string, check func(*entity.Dispute) error,
+) (*entity.Dispute, error) {
+	var dispute *entity.Dispute
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.transactions.findByIDForUpdate(ctx, transactionID); err != nil {
+			return err
+		}
+
+		var err error
+		dispute, err = s.findByID(ctx, transactionID, id)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err = check(dispute); err != nil {
+				return err
+			}
+		}
+
+		if err = transitionDispute(dispute, status); err != nil {
+			return err
+		}
+		resolvedAt := time.Now()
+		dispute.ResolvedAt = &resolvedAt
+		dispute.UpdatedAt = resolvedAt
+
+		if err = s.repository.Update(ctx, dispute); err != nil {
+			return err
+		}
+
+		eventType := DisputeWonEvent
+		if dispute.Status == DisputeLost {
+			eventType = DisputeLostEvent
+			_, err = s.transactions.change(ctx, transactionID, TransactionChargedBackEvent, HistoryChargedBack,
+				chargeBack(dispute.Amount))
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.outbox.Enqueue(ctx, transactionID, eventType, s.mapper.ToDTO(dispute))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (s *DisputeService) GetByID(ctx context.Context, transactionID, id uuid.UUID) (*dto.Dispute, error) {
+	dispute, err := s.findByID(ctx, transactionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(dispute), nil
+}
+
+// GetByTransactionID returns the disputes of a transaction, oldest first.
+func (s *DisputeService) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]dto.Dispute, error) {
+	if _, err := s.transactions.findByID(ctx, transactionID); err != nil {
+		return nil, err
+	}
+
+	disputes, err := s.repository.FindByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Dispute, len(disputes))
+	for i := range disputes {
+		dtos[i] = *s.mapper.ToDTO(&disputes[i])
+	}
+
+	return dtos, nil
+}
+
+func (s *DisputeService) findByID(ctx context.Context, transactionID, id uuid.UUID) (*entity.Dispute, error) {
+	dispute, err := s.repository.FindByID(ctx, transactionID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrDisputeNotFound
+	}
+
+	return dispute, err
+}
+
+// chargeBack returns the change adding a lost dispute of amount to the charged back amount. The
+// status of the transaction does not change.
+func chargeBack(amount int64) func(*entity.Transaction) (string, error) {
+	return func(transaction *entity.Transaction) (string, error) {
+		if available := refundableAmount(transaction) - transaction.RefundedAmount; amount > available {
+			return "", fmt.Errorf("%w: only %d is neither refunded nor charged back", ErrDisputeExceedsAmount, available)
+		}
+		transaction.ChargedBackAmount += amount
+
+		return "", nil
+	}
+}
+
+// overdue accepts a dispute that is still opened after its evidence deadline.
+func overdue(dispute *entity.Dispute) error {
+	if dispute.Status != DisputeOpened || dispute.EvidenceDueAt.After(time.Now()) {
+		return errDisputeClosed
+	}
+
+	return nil
+}
+
+// unclaimedAmount is what remains of the collected amount of a locked transaction once pending and
+// succeeded refunds and open disputes are set aside. Lost disputes are already deducted by
+// refundableAmount.
+func unclaimedAmount(ctx context.Context, transaction *entity.Transaction, refunds, disputes ClaimedAmounts) (int64, error) {
+	refunded, err := refunds.SumAmount(ctx, transaction.ID, []string{RefundPending, RefundSucceeded})
+	if err != nil {
+		return 0, err
+	}
+
+	disputed, err := disputes.SumAmount(ctx, transaction.ID, openDisputeStatuses)
+	if err != nil {
+		return 0, err
+	}
+
+	return refundableAmount(transaction) - refunded - disputed, nil
+}
+
+func validateEvidence(files []dto.EvidenceFile) error {
+	if len(files) == 0 {
+		return fmt.Errorf("%w: at least one evidence file is required", ErrInvalidInput)
+	}
+
+	for _, file := range files {
+		if file.Name == "" || len(file.Name) > 255 {
+			return fmt.Errorf("%w: evidence name must be 1 to 255 characters", ErrInvalidInput)
+		}
+		if file.URL == "" {
+			return fmt.Errorf("%w: evidence %q needs a url", ErrInvalidInput, file.Name)
+		}
+		if file.Size < 0 {
+			return fmt.Errorf("%w: size of evidence %q must not be negative", ErrInvalidInput, file.Name)
+		}
+	}
+
+	return nil
+}
+
+// transitionDispute moves dispute to status if the dispute status graph allows it.
+func transitionDispute(dispute *entity.Dispute, status string) error {
+	if _, ok := disputeTransitions[status]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, status)
+	}
+
+	for _, next := range disputeTransitions[dispute.Status] {
+		if next == status {
+			dispute.Status = status
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: dispute %q to %q", ErrInvalidTransition, dispute.Status, status)
+}
@@ -0,0 +1,54 @@
+package service
+
+import "errors"
+
+var (
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrUnknownStatus       = errors.New("unknown status")
+	ErrInvalidTransition   = errors.New("invalid status transition")
+	ErrTotalMismatch       = errors.New("amount does not match the line items")
+	ErrSplitMismatch       = errors.New("split parts do not sum to the transaction amount")
+	ErrPatchConflict       = errors.New("patch does not apply to the current transaction")
+
+	ErrRefundNotFound      = errors.New("refund not found")
+	ErrNotRefundable       = errors.New("transaction cannot be refunded")
+	ErrRefundExceedsAmount = errors.New("refunds exceed the transaction amount")
+
+	ErrAuthorizationExpired        = errors.New("authorization expired")
+	ErrCaptureExceedsAuthorization = errors.New("capture exceeds the authorized amount")
+
+	ErrMerchantNotFound   = errors.New("merchant not found")
+	ErrCustomerNotFound   = errors.New("customer not found")
+	ErrUnknownMerchant    = errors.New("unknown merchant")
+	ErrUnknownCustomer    = errors.New("unknown customer")
+	ErrDuplicateReference = errors.New("external reference already in use")
+
+	ErrLedgerAccountNotFound = errors.New("ledger account not found")
+	ErrUnbalancedJournal     = errors.New("journal does not balance")
+
+	ErrPricingRuleNotFound = errors.New("pricing rule not found")
+	ErrChargesExceedAmount = errors.New("fees and tax exceed the transaction amount")
+	ErrRateUnavailable     = errors.New("exchange rate unavailable")
+
+	ErrRiskRuleNotFound       = errors.New("risk rule not found")
+	ErrRiskAssessmentNotFound = errors.New("risk assessment not found")
+
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrNotDisputable          = errors.New("transaction cannot be disputed")
+	ErrDisputeExceedsAmount   = errors.New("disputes exceed the transaction amount")
+	ErrEvidenceDeadlinePassed = errors.New("evidence deadline passed")
+
+	ErrSettlementBatchNotFound = errors.New("settlement batch not found")
+
+	ErrScheduleNotFound = errors.New("schedule not found")
+
+	ErrJobNotRun = errors.New("job has not run yet")
+
+	ErrLimitNotFound       = errors.New("limit not found")
+	ErrCountLimitExceeded  = errors.New("transaction count limit exceeded")
+	ErrAmountLimitExceeded = errors.New("transaction amount limit exceeded")
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	TransactionExpiredEvent = "transaction.expired"
+
+	expiryJob       = "expiry"
+	expiryLockKey   = 5_000_007
+	expiryBatchSize = 100
+)
+
+// expirableStatuses are the statuses a stale transaction can expire from.
+var expirableStatuses = []string{pendingStatus, createdStatus}
+
+// errNotStale reports a transaction that changed since it was found stale.
+var errNotStale = errors.New("transaction is not stale anymore")
+
+type ExpiryRepository interface {
+	FindStale(ctx context.Context, statuses []string, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+type ExpiryTransactions interface {
+	change(
+		ctx context.Context, id uuid.UUID, eventType, action string, fn func(*entity.Transaction) (string, error),
+	) (*dto.Transaction, error)
+}
+
+type JobRunRepository interface {
+	Save(ctx context.Context, run *entity.JobRun) error
+	FindByName(ctx context.Context, name string) (*entity.JobRun, error)
+}
+
+type JobRunMapper interface {
+	ToDTO(run *entity.JobRun) *dto.JobRun
+}
+
+// ExpiryService expires the transactions left in pending or created for longer than a TTL.
+type ExpiryService struct {
+	locker       Locker
+	repository   ExpiryRepository
+	transactions ExpiryTransactions
+	runs         JobRunRepository
+	mapper       JobRunMapper
+	statuses     []string
+	ttl          time.Duration
+}
+
+func NewExpiryService(
+	locker Locker,
+	repository ExpiryRepository,
+	transactions ExpiryTransactions,
+	runs JobRunRepository,
+	mapper JobRunMapper,
+	statuses []string,
+	ttl time.Duration) *ExpiryService {
+	return &ExpiryService{
+		locker:       locker,
+		repository:   repository,
+		transactions: transactions,
+		runs:         runs,
+		mapper:       mapper,
+		statuses:     statuses,
+		ttl:          ttl,
+	}
+}
+
+// ValidateExpiryStatuses checks that transactions can expire from each of statuses.
+func ValidateExpiryStatuses(statuses []string) error {
+	for _, status := range statuses {
+		if !slices.Contains(expirableStatuses, status) {
+			return fmt.Errorf("%w: transactions cannot expire from %q", ErrInvalidInput, status)
+		}
+	}
+
+	return nil
+}
+
+// Expire moves the transactions in one of the configured statuses that were not updated for the TTL
+// to expired, publishing transaction.expired for each, and records the outcome of the run. Only one
+// replica sweeps a batch at a time, and a run that never got the lock is not recorded. A zero TTL
+// disables expiry.
+func (s *ExpiryService) Expire(ctx context.Context) error {
+	if s.ttl <= 0 || len(s.statuses) == 0 {
+		return nil
+	}
+
+	run := &entity.JobRun{Name: expiryJob, StartedAt: time.Now()}
+	cutoff := run.StartedAt.Add(-s.ttl)
+	swept := false
+
+	var err error
+	for {
+		var locked bool
+		var found int
+		locked, found, err = s.sweep(ctx, cutoff, run)
+		swept = swept || locked
+		if err != nil || !locked || found < expiryBatchSize {
+			break
+		}
+	}
+
+	if !swept {
+		return err
+	}
+	run.FinishedAt = time.Now()
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	return errors.Join(err, s.runs.Save(context.WithoutCancel(ctx), run))
+}
+
+// sweep expires a batch of the transactions found stale at cutoff, counting them in run. It reports
+// whether it got the lock and how many transactions it found.
+func (s *ExpiryService) sweep(ctx context.Context, cutoff time.Time, run *entity.JobRun) (bool, int, error) {
+	found, expired := 0, int64(0)
+	locked, err := s.locker.WithAdvisoryLock(ctx, expiryLockKey, func(ctx context.Context) error {
+		ids, err := s.repository.FindStale(ctx, s.statuses, cutoff, expiryBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			_, err = s.transactions.change(ctx, id, TransactionExpiredEvent, HistoryStale, s.expire(cutoff))
+			switch {
+			case err == nil:
+				expired++
+			case !errors.Is(err, errNotStale) && !errors.Is(err, ErrTransactionNotFound):
+				return err
+			}
+		}
+		found = len(ids)
+
+		return nil
+	})
+	if err == nil {
+		run.Processed += expired
+	}
+
+	return locked, found, err
+}
+
+// expire moves a transaction found stale at cutoff to expired unless it changed since.
+func (s *ExpiryService) expire(cutoff time.Time) func(*entity.Transaction) (string, error) {
+	return func(transaction *entity.Transaction) (string, error) {
+		if !slices.Contains(s.statuses, transaction.Status.Name) || !transaction.UpdatedAt.Before(cutoff) {
+			return "", errNotStale
+		}
+
+		return expiredStatus, nil
+	}
+}
+
+// LastRun returns the outcome of the last expiry run of any replica.
+func (s *ExpiryService) LastRun(ctx context.Context) (*dto.JobRun, error) {
+	run, err := s.runs.FindByName(ctx, expiryJob)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotRun, expiryJob)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(run), nil
+}
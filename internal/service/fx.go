@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/fx"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+// rateDecimals is the precision of the rates recorded on transactions and reports.
+const rateDecimals = 12
+
+// RateProvider returns the amount of to worth one unit of from.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+type TotalsRepository interface {
+	SumByCurrency(ctx context.Context, query repository.TransactionQuery) ([]repository.CurrencyTotal, error)
+}
+
+// FXService converts transaction amounts between currencies with the rates of a RateProvider.
+type FXService struct {
+	provider           RateProvider
+	totals             TotalsRepository
+	statusRepository   StatusRepository
+	settlementCurrency string
+}
+
+func NewFXService(
+	provider RateProvider, totals TotalsRepository, statusRepository StatusRepository, settlementCurrency string) *FXService {
+	return &FXService{
+		provider:           provider,
+		totals:             totals,
+		statusRepository:   statusRepository,
+		settlementCurrency: currency.Normalize(settlementCurrency),
+	}
+}
+
+// ValidateSettlementCurrency checks the settlement currency transactions are converted to. An empty
+// one disables conversion; any other needs exchange rates to convert the other currencies.
+func ValidateSettlementCurrency(code string, hasRates bool) error {
+	if code == "" {
+		return nil
+	}
+	if !hasRates {
+		return fmt.Errorf("settlement currency %s needs exchange rates", code)
+	}
+
+	return currency.Validate(code)
+}
+
+// Convert records the settlement amount of transaction and the rate it was converted at. Without a
+// settlement currency transactions are not converted.
+func (s *FXService) Convert(ctx context.Context, transaction *entity.Transaction) error {
+	if s.settlementCurrency == "" {
+		transaction.SettlementCurrency = ""
+		transaction.SettlementAmount = 0
+		transaction.FxRate = ""
+		return nil
+	}
+
+	rate, err := s.rate(ctx, transaction.Currency, s.settlementCurrency)
+	if err != nil {
+		return err
+	}
+
+	amount, err := convertAmount(transaction.Amount, transaction.Currency, s.settlementCurrency, rate)
+	if err != nil {
+		return err
+	}
+
+	transaction.SettlementCurrency = s.settlementCurrency
+	transaction.SettlementAmount = amount
+	transaction.FxRate = formatRate(rate)
+
+	return nil
+}
+
+// Totals sums the transactions selected by filter per currency and converts the sums to
+// filter.Currency at the current rates.
+func (s *FXService) Totals(ctx context.Context, filter dto.TotalsFilter) (*dto.Totals, error) {
+	target := currency.Normalize(filter.Currency)
+	if err := currency.Validate(target); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	query := repository.TransactionQuery{
+		MerchantID:     filter.MerchantID,
+		CustomerID:     filter.CustomerID,
+		CreatedFrom:    filter.CreatedFrom,
+		CreatedTo:      filter.CreatedTo,
+		IncludeDeleted: filter.IncludeDeleted,
+	}
+	if filter.Status != "" {
+		status, err := findStatusByName(s.statusRepository, filter.Status)
+		if err != nil {
+			return nil, err
+		}
+		query.StatusID = &status.ID
+	}
+
+	sums, err := s.totals.SumByCurrency(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &dto.Totals{Currency: target, Currencies: make([]dto.CurrencyTotal, 0, len(sums))}
+	for _, sum := range sums {
+		rate, err := s.rate(ctx, sum.Currency, target)
+		if err != nil {
+			return nil, err
+		}
+		converted, err := convertAmount(sum.Amount, sum.Currency, target, rate)
+		if err != nil {
+			return nil, err
+		}
+
+		totals.Amount += converted
+		totals.Count += sum.Count
+		totals.Currencies = append(totals.Currencies, dto.CurrencyTotal{
+			Currency:  sum.Currency,
+			Amount:    sum.Amount,
+			Count:     sum.Count,
+			Rate:      formatRate(rate),
+			Converted: converted,
+		})
+	}
+
+	return totals, nil
+}
+
+func (s *FXService) rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	rate, err := s.provider.Rate(ctx, from, to)
+	if errors.Is(err, fx.ErrRateUnavailable) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrRateUnavailable, from, to)
+	}
+
+	return rate, err
+}
+
+// convertAmount converts amount minor units of from to minor units of to, rounding half up.
+func convertAmount(amount int64, from, to string, rate *big.Rat) (int64, error) {
+	fromFactor, err := currency.Factor(from)
+	if err != nil {
+		return 0, err
+	}
+	toFactor, err := currency.Factor(to)
+	if err != nil {
+		return 0, err
+	}
+
+	value := new(big.Rat).SetInt64(amount)
+	value.Mul(value, rate)
+	value.Mul(value, big.NewRat(toFactor, fromFactor))
+	value.Add(value, big.NewRat(1, 2))
+
+	converted := new(big.Int).Div(value.Num(), value.Denom())
+	if !converted.IsInt64() {
+		return 0, fmt.Errorf("%w: %d %s does not fit in %s", ErrInvalidInput, amount, from, to)
+	}
+
+	return converted.Int64(), nil
+}
+
+// formatRate writes rate as a decimal of at most rateDecimals digits.
+func formatRate(rate *big.Rat) string {
+	text := rate.FloatString(rateDecimals)
+	text = strings.TrimRight(text, "0")
+
+	return strings.TrimSuffix(text, ".")
+}
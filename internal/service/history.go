@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+	"github.com/the-great-checkout/transactions-crud/internal/requestctx"
+)
+
+const (
+	HistoryCreated = "created"
+	HistoryUpdated = "updated"
+	HistoryDeleted = "deleted"
+
+	HistoryAuthorized = "authorized"
+	HistoryCaptured   = "captured"
+	HistoryVoided     = "voided"
+	HistoryExpired    = "authorization_expired"
+
+	HistoryApproved = "approved"
+	HistoryDeclined = "declined"
+
+	HistoryChargedBack = "charged_back"
+
+	HistorySplit = "split"
+
+	HistoryStale = "expired"
+
+	HistoryRestored = "restored"
+)
+
+type HistoryRepository interface {
+	Create(ctx context.Context, event *entity.TransactionEvent) error
+	FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]entity.TransactionEvent, error)
+	FindLast(ctx context.Context, transactionID uuid.UUID, action string) (*entity.TransactionEvent, error)
+}
+
+type HistoryMapper interface {
+	ToDTO(event *entity.TransactionEvent) *dto.TransactionEvent
+}
+
+type HistoryService struct {
+	repository   HistoryRepository
+	transactions ProjectionSource
+	mapper       HistoryMapper
+}
+
+func NewHistoryService(repository HistoryRepository, transactions ProjectionSource, mapper HistoryMapper) *HistoryService {
+	return &HistoryService{repository: repository, transactions: transactions, mapper: mapper}
+}
+
+// Record appends a change of a transaction to its history, attributed to the actor and request
+// in ctx. Call it inside the transaction that writes the change.
+func (s *HistoryService) Record(ctx context.Context, action string, before, after *dto.Transaction) error {
+	event := &entity.TransactionEvent{
+		Action:    action,
+		Actor:     requestctx.Actor(ctx),
+		RequestID: requestctx.RequestID(ctx),
+		CreatedAt: time.Now(),
+	}
+
+	var err error
+	if before != nil {
+		event.TransactionID = before.ID
+		if event.Old, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		event.TransactionID = after.ID
+		if event.New, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	return s.repository.Create(ctx, event)
+}
+
+// GetByTransactionID returns the history of a transaction, deleted ones included, oldest first.
+func (s *HistoryService) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]dto.TransactionEvent, error) {
+	_, err := s.transactions.FindByIDWithDeleted(ctx, transactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repository.FindByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.TransactionEvent, len(events))
+	for i := range events {
+		dtos[i] = *s.mapper.ToDTO(&events[i])
+	}
+
+	return dtos, nil
+}
+
+// StatusBefore returns the status a transaction had before its latest change recorded as action.
+func (s *HistoryService) StatusBefore(ctx context.Context, transactionID uuid.UUID, action string) (string, error) {
+	event, err := s.repository.FindLast(ctx, transactionID, action)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", fmt.Errorf("%w: no %s change in the history of the transaction", ErrInvalidTransition, action)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var before dto.Transaction
+	if err = json.Unmarshal(event.Old, &before); err != nil {
+		return "", err
+	}
+
+	return before.Status, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyRepository interface {
+	Create(key *entity.IdempotencyKey) (bool, error)
+	Reclaim(key string, cutoff, now time.Time) (bool, error)
+	FindByKey(key string) (*entity.IdempotencyKey, error)
+	Update(key *entity.IdempotencyKey) error
+	Delete(key string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// IdempotencyService keeps the responses of requests by key for retention. A request that did not
+// complete its key within lease, because its process crashed for example, is taken over by a retry.
+type IdempotencyService struct {
+	repository IdempotencyRepository
+	retention  time.Duration
+	lease      time.Duration
+}
+
+func NewIdempotencyService(repository IdempotencyRepository, retention, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{repository: repository, retention: retention, lease: lease}
+}
+
+// Begin claims key for a request with the given fingerprint. It returns the stored response when the
+// key was already completed by an identical request, or nil when the caller should handle the request,
+// including when an identical request claimed it longer than the lease ago without completing it.
+func (s *IdempotencyService) Begin(key, fingerprint string) (*dto.IdempotentResponse, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: idempotency key longer than %d characters", ErrInvalidInput, maxIdempotencyKeyLength)
+	}
+
+	now := time.Now()
+	created, err := s.repository.Create(&entity.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ClaimedAt:   now,
+		ExpiresAt:   now.Add(s.retention),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+
+	existing, err := s.repository.FindByKey(key)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Released concurrently, claim it again.
+		return s.Begin(key, fingerprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.ExpiresAt.Before(now) {
+		if err = s.repository.Delete(key); err != nil {
+			return nil, err
+		}
+		return s.Begin(key, fingerprint)
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.StatusCode == 0 {
+		cutoff := now.Add(-s.lease)
+		if !existing.ClaimedAt.Before(cutoff) {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		reclaimed, err := s.repository.Reclaim(key, cutoff, now)
+		if err != nil {
+			return nil, err
+		}
+		if !reclaimed {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, nil
+	}
+
+	return &dto.IdempotentResponse{StatusCode: existing.StatusCode, Body: existing.Body}, nil
+}
+
+// Complete stores the response of the request that claimed key so retries can replay it.
+func (s *IdempotencyService) Complete(key string, response *dto.IdempotentResponse) error {
+	existing, err := s.repository.FindByKey(key)
+	if err != nil {
+		return err
+	}
+
+	existing.StatusCode = response.StatusCode
+	existing.Body = response.Body
+
+	return s.repository.Update(existing)
+}
+
+// Release forgets key so that the request can be retried, e.g. after a server error.
+func (s *IdempotencyService) Release(key string) error {
+	return s.repository.Delete(key)
+}
+
+// PurgeExpired removes keys older than the retention window.
+func (s *IdempotencyService) PurgeExpired() (int64, error) {
+	return s.repository.DeleteExpired(time.Now())
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+// Ledger accounts. Receivable holds what customers owe, cash what was collected, revenue what was
+// earned, refunds what was given back and chargebacks what was taken back by lost disputes.
+const (
+	receivableAccount  = "receivable"
+	cashAccount        = "cash"
+	revenueAccount     = "revenue"
+	refundsAccount     = "refunds"
+	chargebacksAccount = "chargebacks"
+)
+
+var ledgerAccountTypes = map[string]string{
+	receivableAccount:  "asset",
+	cashAccount:        "asset",
+	revenueAccount:     "revenue",
+	refundsAccount:     "contra_revenue",
+	chargebacksAccount: "contra_revenue",
+}
+
+// collectedStatuses are the statuses in which the amount of a transaction that was never
+// authorized counts as collected.
+var collectedStatuses = map[string]bool{
+	completedStatus:         true,
+	partiallyRefundedStatus: true,
+	refundedStatus:          true,
+}
+
+// ledgerMetrics is published at /debug/vars.
+var ledgerMetrics = expvar.NewMap("ledger")
+
+type LedgerRepository interface {
+	FindOrCreateAccount(ctx context.Context, code, currency, accountType string) (*entity.LedgerAccount, error)
+	CreateEntry(ctx context.Context, entry *entity.JournalEntry) error
+	FindBalances(ctx context.Context) ([]repository.AccountBalance, error)
+	FindBalance(ctx context.Context, accountID uuid.UUID) (*repository.AccountBalance, error)
+	FindEntries(ctx context.Context, query repository.JournalQuery) ([]entity.JournalEntry, error)
+	FindUnbalancedEntryIDs(ctx context.Context) ([]uuid.UUID, error)
+	SumByCurrency(ctx context.Context) (map[string]int64, error)
+}
+
+type LedgerMapper interface {
+	AccountToDTO(account *entity.LedgerAccount, balance int64) *dto.LedgerAccount
+	EntryToDTO(entry *entity.JournalEntry) *dto.JournalEntry
+}
+
+// LedgerService keeps a double-entry ledger of the money moved by transactions.
+type LedgerService struct {
+	repository LedgerRepository
+	mapper     LedgerMapper
+}
+
+func NewLedgerService(repository LedgerRepository, mapper LedgerMapper) *LedgerService {
+	return &LedgerService{repository: repository, mapper: mapper}
+}
+
+// Post records the money moved by a change of a transaction from before to after as a balanced
+// journal entry. Changes that move no money post nothing. Call it inside the transaction that
+// writes the change.
+func (s *LedgerService) Post(ctx context.Context, eventType string, before, after *dto.Transaction) error {
+	amounts := ledgerMovements(before, after)
+
+	var total int64
+	entry := &entity.JournalEntry{TransactionID: after.ID, EventType: eventType, CreatedAt: time.Now()}
+	for _, code := range []string{receivableAccount, cashAccount, revenueAccount, refundsAccount, chargebacksAccount} {
+		if amounts[code] == 0 {
+			continue
+		}
+
+		account, err := s.repository.FindOrCreateAccount(ctx, code, after.Currency, ledgerAccountTypes[code])
+		if err != nil {
+			return err
+		}
+		entry.Postings = append(entry.Postings, entity.Posting{AccountID: account.ID, Amount: amounts[code]})
+		total += amounts[code]
+	}
+
+	if len(entry.Postings) == 0 {
+		return nil
+	}
+	if total != 0 {
+		return fmt.Errorf("%w: %s of transaction %s is off by %d", ErrUnbalancedJournal, eventType, after.ID, total)
+	}
+
+	return s.repository.CreateEntry(ctx, entry)
+}
+
+func (s *LedgerService) GetAccounts(ctx context.Context) ([]dto.LedgerAccount, error) {
+	balances, err := s.repository.FindBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.LedgerAccount, len(balances))
+	for i := range balances {
+		dtos[i] = *s.mapper.AccountToDTO(&balances[i].LedgerAccount, balances[i].Balance)
+	}
+
+	return dtos, nil
+}
+
+func (s *LedgerService) GetAccount(ctx context.Context, id uuid.UUID) (*dto.LedgerAccount, error) {
+	balance, err := s.repository.FindBalance(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrLedgerAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.AccountToDTO(&balance.LedgerAccount, balance.Balance), nil
+}
+
+// GetEntries returns the newest journal entries matching filter.
+func (s *LedgerService) GetEntries(ctx context.Context, filter dto.JournalFilter) ([]dto.JournalEntry, error) {
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repository.FindEntries(ctx, repository.JournalQuery{
+		TransactionID: filter.TransactionID,
+		AccountID:     filter.AccountID,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.JournalEntry, len(entries))
+	for i := range entries {
+		dtos[i] = *s.mapper.EntryToDTO(&entries[i])
+	}
+
+	return dtos, nil
+}
+
+// Check looks for journal entries that do not balance and for currencies whose postings do not
+// sum to zero.
+func (s *LedgerService) Check(ctx context.Context) (*dto.LedgerCheck, error) {
+	unbalanced, err := s.repository.FindUnbalancedEntryIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repository.SumByCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imbalance := map[string]int64{}
+	for code, total := range totals {
+		if total != 0 {
+			imbalance[code] = total
+		}
+	}
+
+	count := new(expvar.Int)
+	count.Set(int64(len(unbalanced)))
+	ledgerMetrics.Set("unbalanced_entries", count)
+
+	return &dto.LedgerCheck{
+		UnbalancedEntries: append([]uuid.UUID{}, unbalanced...),
+		Imbalance:         imbalance,
+		CheckedAt:         time.Now(),
+	}, nil
+}
+
+// Verify runs Check and fails with ErrUnbalancedJournal when the ledger does not balance.
+func (s *LedgerService) Verify(ctx context.Context) error {
+	check, err := s.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(check.UnbalancedEntries) > 0 || len(check.Imbalance) > 0 {
+		return fmt.Errorf("%w: %d unbalanced entries, imbalance %v",
+			ErrUnbalancedJournal, len(check.UnbalancedEntries), check.Imbalance)
+	}
+
+	return nil
+}
+
+// ledgerMovements returns the amount to post to each account for a change from before to after.
+// Booked amounts move between receivable and revenue, collected amounts from receivable to cash,
+// refunded amounts from cash to refunds and charged back amounts from cash to chargebacks.
+// The money of a split transaction moves through its children, so splitting it reverses what it
+// booked and later changes post nothing. Restoring a deleted transaction books again what its
+// deletion wrote off.
+func ledgerMovements(before, after *dto.Transaction) map[string]int64 {
+	deleted := after.Status == deletedStatus
+	restored := before != nil && before.Status == deletedStatus && !deleted
+	before, after = unsplit(before), unsplit(after)
+
+	bookedBefore, collectedBefore := booked(before), collected(before)
+	bookedAfter, collectedAfter := booked(after), collected(after)
+	if deleted && before != nil {
+		// Deleting writes off what was not collected yet.
+		bookedAfter, collectedAfter = collectedBefore, collectedBefore
+	}
+	if restored && before != nil {
+		// The deletion left only what was collected booked.
+		bookedBefore, collectedBefore = collectedAfter, collectedAfter
+	}
+
+	bookedDelta := bookedAfter - bookedBefore
+	collectedDelta := collectedAfter - collectedBefore
+	refundedDelta := refunded(after) - refunded(before)
+	chargedBackDelta := chargedBack(after) - chargedBack(before)
+
+	return map[string]int64{
+		receivableAccount:  bookedDelta - collectedDelta,
+		revenueAccount:     -bookedDelta,
+		cashAccount:        collectedDelta - refundedDelta - chargedBackDelta,
+		refundsAccount:     refundedDelta,
+		chargebacksAccount: chargedBackDelta,
+	}
+}
+
+// unsplit returns nil for a split transaction, which weighs nothing in the ledger.
+func unsplit(transaction *dto.Transaction) *dto.Transaction {
+	if transaction == nil || transaction.SplitType != "" {
+		return nil
+	}
+
+	return transaction
+}
+
+// booked is the amount a transaction is expected to bring in, net of voided authorizations.
+// Declined and expired transactions bring in nothing.
+func booked(transaction *dto.Transaction) int64 {
+	if transaction == nil || transaction.Status == declinedStatus || transaction.Status == expiredStatus {
+		return 0
+	}
+
+	return transaction.Amount - transaction.VoidedAmount
+}
+
+// collected is the captured amount of an authorized transaction, or the whole amount of a
+// transaction that was completed without authorization.
+func collected(transaction *dto.Transaction) int64 {
+	switch {
+	case transaction == nil:
+		return 0
+	case transaction.AuthorizedAmount > 0:
+		return transaction.CapturedAmount
+	case collectedStatuses[transaction.Status]:
+		return transaction.Amount
+	default:
+		return 0
+	}
+}
+
+func refunded(transaction *dto.Transaction) int64 {
+	if transaction == nil {
+		return 0
+	}
+
+	return transaction.RefundedAmount
+}
+
+func chargedBack(transaction *dto.Transaction) int64 {
+	if transaction == nil {
+		return 0
+	}
+
+	return transaction.ChargedBackAmount
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+func TestLedgerMovements(t *testing.T) {
+	transaction := func(status string, amount int64) *dto.Transaction {
+		return &dto.Transaction{Status: status, Amount: amount}
+	}
+	authorized := func(status string, captured, voided int64) *dto.Transaction {
+		return &dto.Transaction{Status: status, Amount: 1000, AuthorizedAmount: 1000, CapturedAmount: captured, VoidedAmount: voided}
+	}
+	refunded := func(status string, refunded, chargedBack int64) *dto.Transaction {
+		return &dto.Transaction{Status: status, Amount: 1000, RefundedAmount: refunded, ChargedBackAmount: chargedBack}
+	}
+	split := &dto.Transaction{Status: splitStatus, Amount: 1000, SplitType: SplitInstallments}
+
+	tests := []struct {
+		name   string
+		before *dto.Transaction
+		after  *dto.Transaction
+		want   map[string]int64
+	}{
+		{"create", nil, transaction(createdStatus, 1000),
+			map[string]int64{receivableAccount: 1000, revenueAccount: -1000}},
+		{"create completed", nil, transaction(completedStatus, 1000),
+			map[string]int64{revenueAccount: -1000, cashAccount: 1000}},
+		{"create declined", nil, transaction(declinedStatus, 1000), nil},
+		{"complete", transaction(createdStatus, 1000), transaction(completedStatus, 1000),
+			map[string]int64{receivableAccount: -1000, cashAccount: 1000}},
+		{"adjust pending amount", transaction(createdStatus, 1000), transaction(createdStatus, 1500),
+			map[string]int64{receivableAccount: 500, revenueAccount: -500}},
+		{"adjust completed amount", transaction(completedStatus, 1000), transaction(completedStatus, 800),
+			map[string]int64{revenueAccount: 200, cashAccount: -200}},
+		{"authorize", nil, authorized(authorizedStatus, 0, 0),
+			map[string]int64{receivableAccount: 1000, revenueAccount: -1000}},
+		{"partial capture", authorized(authorizedStatus, 0, 0), authorized(partiallyCapturedStatus, 600, 0),
+			map[string]int64{receivableAccount: -600, cashAccount: 600}},
+		{"capture the rest", authorized(partiallyCapturedStatus, 600, 0), authorized(completedStatus, 1000, 0),
+			map[string]int64{receivableAccount: -400, cashAccount: 400}},
+		{"void the rest", authorized(partiallyCapturedStatus, 600, 0), authorized(completedStatus, 600, 400),
+			map[string]int64{receivableAccount: -400, revenueAccount: 400}},
+		{"void", authorized(authorizedStatus, 0, 0), authorized(voidedStatus, 0, 1000),
+			map[string]int64{receivableAccount: -1000, revenueAccount: 1000}},
+		{"partial refund", refunded(completedStatus, 0, 0), refunded(partiallyRefundedStatus, 300, 0),
+			map[string]int64{cashAccount: -300, refundsAccount: 300}},
+		{"full refund", refunded(partiallyRefundedStatus, 300, 0), refunded(refundedStatus, 1000, 0),
+			map[string]int64{cashAccount: -700, refundsAccount: 700}},
+		{"chargeback", refunded(completedStatus, 0, 0), refunded(completedStatus, 0, 250),
+			map[string]int64{cashAccount: -250, chargebacksAccount: 250}},
+		{"chargeback after refund", refunded(partiallyRefundedStatus, 300, 0), refunded(partiallyRefundedStatus, 300, 700),
+			map[string]int64{cashAccount: -700, chargebacksAccount: 700}},
+		{"expire", transaction(createdStatus, 1000), transaction(expiredStatus, 1000),
+			map[string]int64{receivableAccount: -1000, revenueAccount: 1000}},
+		{"delete pending", transaction(createdStatus, 1000), transaction(deletedStatus, 1000),
+			map[string]int64{receivableAccount: -1000, revenueAccount: 1000}},
+		{"delete completed", transaction(completedStatus, 1000), transaction(deletedStatus, 1000), nil},
+		{"delete partially captured", authorized(partiallyCapturedStatus, 600, 0), authorized(deletedStatus, 600, 0),
+			map[string]int64{receivableAccount: -400, revenueAccount: 400}},
+		{"restore", transaction(deletedStatus, 1000), transaction(createdStatus, 1000),
+			map[string]int64{receivableAccount: 1000, revenueAccount: -1000}},
+		{"split", transaction(createdStatus, 1000), split,
+			map[string]int64{receivableAccount: -1000, revenueAccount: 1000}},
+		{"split parent changes", split, &dto.Transaction{Status: completedStatus, Amount: 1000, SplitType: SplitInstallments}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ledgerMovements(test.before, test.after)
+
+			var sum int64
+			for _, amount := range got {
+				sum += amount
+			}
+			if sum != 0 {
+				t.Fatalf("movements %v sum to %d, want debits and credits to balance", got, sum)
+			}
+
+			want := map[string]int64{
+				receivableAccount: 0, revenueAccount: 0, cashAccount: 0, refundsAccount: 0, chargebacksAccount: 0,
+			}
+			maps.Copy(want, test.want)
+			if !maps.Equal(got, want) {
+				t.Fatalf("ledgerMovements() = %v, want %v", got, want)
+			}
+		})
+	}
+}
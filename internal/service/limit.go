@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	LimitCreate  = "create"
+	LimitCapture = "capture"
+
+	merchantScope = "merchant"
+	customerScope = "customer"
+
+	dailyPeriod   = "daily"
+	monthlyPeriod = "monthly"
+
+	// limitUsageRetention keeps the usage of the previous monthly period.
+	limitUsageRetention = 62 * 24 * time.Hour
+)
+
+type LimitRepository interface {
+	Create(ctx context.Context, limit *entity.Limit) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Limit, error)
+	FindAll(ctx context.Context) ([]entity.Limit, error)
+	FindApplicable(ctx context.Context, scope string, subjectID uuid.UUID, operation string) ([]entity.Limit, error)
+	Update(ctx context.Context, limit *entity.Limit) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Increment(ctx context.Context, usage *entity.LimitUsage, maxCount, maxAmount int64) (bool, error)
+	FindUsage(ctx context.Context, limitID, subjectID uuid.UUID, periodStart time.Time) (*entity.LimitUsage, error)
+	DeleteUsageBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type LimitMapper interface {
+	ToDTO(limit *entity.Limit) *dto.Limit
+	FromDTO(limit *dto.Limit) *entity.Limit
+}
+
+// LimitService manages the transaction limits of merchants and customers and enforces them.
+// Amounts are counted in the settlement currency, so amount limits need one.
+type LimitService struct {
+	repository         LimitRepository
+	merchants          MerchantRepository
+	customers          CustomerRepository
+	mapper             LimitMapper
+	settlementCurrency string
+}
+
+func NewLimitService(
+	repository LimitRepository, merchants MerchantRepository, customers CustomerRepository, mapper LimitMapper,
+	settlementCurrency string) *LimitService {
+	return &LimitService{
+		repository:         repository,
+		merchants:          merchants,
+		customers:          customers,
+		mapper:             mapper,
+		settlementCurrency: currency.Normalize(settlementCurrency),
+	}
+}
+
+func (s *LimitService) Create(ctx context.Context, input *dto.Limit) (*dto.Limit, error) {
+	limit := s.mapper.FromDTO(input)
+	limit.ID = uuid.Nil
+	if err := s.validate(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(limit), nil
+}
+
+func (s *LimitService) GetByID(ctx context.Context, id uuid.UUID) (*dto.Limit, error) {
+	limit, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(limit), nil
+}
+
+func (s *LimitService) GetAll(ctx context.Context) ([]dto.Limit, error) {
+	limits, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Limit, len(limits))
+	for i := range limits {
+		dtos[i] = *s.mapper.ToDTO(&limits[i])
+	}
+
+	return dtos, nil
+}
+
+// Update replaces a limit. Usage consumed in the current period still counts against it.
+func (s *LimitService) Update(ctx context.Context, id uuid.UUID, input *dto.Limit) (*dto.Limit, error) {
+	existing, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.mapper.FromDTO(input)
+	limit.ID = existing.ID
+	limit.CreatedAt = existing.CreatedAt
+	if err = s.validate(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	if err = s.repository.Update(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(limit), nil
+}
+
+func (s *LimitService) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.repository.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrLimitNotFound
+	}
+
+	return err
+}
+
+// GetUsage returns what the merchant or customer of filter consumed of each of its limits in the
+// current period.
+func (s *LimitService) GetUsage(ctx context.Context, filter dto.LimitUsageFilter) ([]dto.LimitUsage, error) {
+	scope, subjectID := merchantScope, filter.MerchantID
+	if filter.CustomerID != nil {
+		scope, subjectID = customerScope, filter.CustomerID
+	}
+	if subjectID == nil || (filter.MerchantID != nil && filter.CustomerID != nil) {
+		return nil, fmt.Errorf("%w: either merchant_id or customer_id is required", ErrInvalidInput)
+	}
+
+	limits, err := s.repository.FindApplicable(ctx, scope, *subjectID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	usages := make([]dto.LimitUsage, 0, len(limits))
+	for i := range limits {
+		limit := &limits[i]
+		start := periodStart(limit.Period, now)
+		usage, err := s.repository.FindUsage(ctx, limit.ID, *subjectID, start)
+		if err != nil {
+			return nil, err
+		}
+
+		usages = append(usages, dto.LimitUsage{
+			LimitID:     limit.ID,
+			Name:        limit.Name,
+			Scope:       limit.Scope,
+			SubjectID:   *subjectID,
+			Operation:   limit.Operation,
+			Period:      limit.Period,
+			PeriodStart: start,
+			PeriodEnd:   periodEnd(limit.Period, start),
+			Count:       usage.Count,
+			MaxCount:    limit.MaxCount,
+			Amount:      usage.Amount,
+			MaxAmount:   limit.MaxAmount,
+			Currency:    s.settlementCurrency,
+		})
+	}
+
+	return usages, nil
+}
+
+// Consume counts an operation on amount minor units of a transaction against the limits of its
+// merchant and customer. Call it inside the transaction that performs the operation so that the
+// usage is rolled back with it.
+func (s *LimitService) Consume(ctx context.Context, operation string, transaction *entity.Transaction, amount int64) error {
+	settlementAmount := settlementShare(transaction, amount)
+	subjects := []struct {
+		scope string
+		id    *uuid.UUID
+	}{
+		{merchantScope, transaction.MerchantID},
+		{customerScope, transaction.CustomerID},
+	}
+
+	for _, subject := range subjects {
+		if subject.id == nil {
+			continue
+		}
+
+		limits, err := s.repository.FindApplicable(ctx, subject.scope, *subject.id, operation)
+		if err != nil {
+			return err
+		}
+		for i := range limits {
+			if err = s.consume(ctx, &limits[i], *subject.id, settlementAmount); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *LimitService) consume(ctx context.Context, limit *entity.Limit, subjectID uuid.UUID, amount int64) error {
+	start := periodStart(limit.Period, time.Now())
+	// Amounts of different currencies do not add up, so amount limits that were set with a
+	// settlement currency are not enforced after it was unset.
+	maxAmount := limit.MaxAmount
+	if s.settlementCurrency == "" {
+		maxAmount = 0
+	}
+	if maxAmount > 0 && amount > maxAmount {
+		return fmt.Errorf("%w: %s allows %d per %s", ErrAmountLimitExceeded, limit.Name, maxAmount, periodUnit(limit.Period))
+	}
+
+	usage := &entity.LimitUsage{LimitID: limit.ID, SubjectID: subjectID, PeriodStart: start, Count: 1, Amount: amount}
+	applied, err := s.repository.Increment(ctx, usage, limit.MaxCount, maxAmount)
+	if err != nil || applied {
+		return err
+	}
+
+	current, err := s.repository.FindUsage(ctx, limit.ID, subjectID, start)
+	if err != nil {
+		return err
+	}
+	if limit.MaxCount > 0 && current.Count >= limit.MaxCount {
+		return fmt.Errorf("%w: %s allows %d per %s", ErrCountLimitExceeded, limit.Name, limit.MaxCount, periodUnit(limit.Period))
+	}
+
+	return fmt.Errorf("%w: %s allows %d per %s and %d is used", ErrAmountLimitExceeded,
+		limit.Name, limit.MaxAmount, periodUnit(limit.Period), current.Amount)
+}
+
+// PurgeUsage deletes the usage of periods that are long over.
+func (s *LimitService) PurgeUsage(ctx context.Context) (int64, error) {
+	return s.repository.DeleteUsageBefore(ctx, time.Now().Add(-limitUsageRetention))
+}
+
+// settlementShare converts amount minor units of transaction to the settlement currency at the
+// rate the transaction was converted at. Transactions without settlement count their own amount.
+func settlementShare(transaction *entity.Transaction, amount int64) int64 {
+	if transaction.SettlementCurrency == "" || transaction.Amount == 0 {
+		return amount
+	}
+	if amount == transaction.Amount {
+		return transaction.SettlementAmount
+	}
+
+	share := new(big.Int).Mul(big.NewInt(amount), big.NewInt(transaction.SettlementAmount))
+	return share.Quo(share, big.NewInt(transaction.Amount)).Int64()
+}
+
+func periodStart(period string, now time.Time) time.Time {
+	now = now.UTC()
+	if period == monthlyPeriod {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func periodEnd(period string, start time.Time) time.Time {
+	if period == monthlyPeriod {
+		return start.AddDate(0, 1, 0)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
+func periodUnit(period string) string {
+	if period == monthlyPeriod {
+		return "month"
+	}
+
+	return "day"
+}
+
+// validate normalizes limit and rejects limits that cannot be enforced.
+func (s *LimitService) validate(ctx context.Context, limit *entity.Limit) error {
+	limit.Name = strings.TrimSpace(limit.Name)
+	switch {
+	case limit.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	case limit.Scope != merchantScope && limit.Scope != customerScope:
+		return fmt.Errorf("%w: scope must be %s or %s", ErrInvalidInput, merchantScope, customerScope)
+	case limit.Operation != LimitCreate && limit.Operation != LimitCapture:
+		return fmt.Errorf("%w: operation must be %s or %s", ErrInvalidInput, LimitCreate, LimitCapture)
+	case limit.Period != dailyPeriod && limit.Period != monthlyPeriod:
+		return fmt.Errorf("%w: period must be %s or %s", ErrInvalidInput, dailyPeriod, monthlyPeriod)
+	case limit.MaxCount < 0 || limit.MaxAmount < 0 || limit.MaxCount+limit.MaxAmount == 0:
+		return fmt.Errorf("%w: max_count or max_amount must be positive and neither negative", ErrInvalidInput)
+	case limit.MaxAmount > 0 && s.settlementCurrency == "":
+		return fmt.Errorf("%w: max_amount needs a settlement currency to count amounts in", ErrInvalidInput)
+	}
+
+	return s.checkSubject(ctx, limit)
+}
+
+// checkSubject rejects a limit on a merchant or customer that does not exist.
+func (s *LimitService) checkSubject(ctx context.Context, limit *entity.Limit) error {
+	if limit.SubjectID == nil {
+		return nil
+	}
+	if limit.Scope == merchantScope {
+		_, err := s.merchants.FindByID(ctx, *limit.SubjectID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownMerchant, limit.SubjectID)
+		}
+		return err
+	}
+
+	_, err := s.customers.FindByID(ctx, *limit.SubjectID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrUnknownCustomer, limit.SubjectID)
+	}
+	return err
+}
+
+func (s *LimitService) findByID(ctx context.Context, id uuid.UUID) (*entity.Limit, error) {
+	limit, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrLimitNotFound
+	}
+
+	return limit, err
+}
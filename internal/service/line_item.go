@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+const (
+	maxLineItems = 100
+	// maxSKULen and maxDescriptionLen match the columns of line_items.
+	maxSKULen         = 64
+	maxDescriptionLen = 255
+)
+
+// priceLineItems validates line items and computes their totals. Without items amount is returned
+// unchanged; with items a zero amount is replaced by their sum and any other amount must equal it.
+func priceLineItems(amount int64, items []dto.LineItem) ([]entity.LineItem, int64, error) {
+	if len(items) == 0 {
+		return nil, amount, nil
+	}
+	if len(items) > maxLineItems {
+		return nil, 0, fmt.Errorf("%w: more than %d line items", ErrInvalidInput, maxLineItems)
+	}
+
+	var sum int64
+	lineItems := make([]entity.LineItem, len(items))
+	for i, item := range items {
+		total, err := lineTotal(item)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line item %d: %w", i+1, err)
+		}
+		if sum > math.MaxInt64-total {
+			return nil, 0, fmt.Errorf("%w: line items total is too large", ErrInvalidInput)
+		}
+		sum += total
+
+		lineItems[i] = entity.LineItem{
+			Position:    i,
+			SKU:         item.SKU,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Tax:         item.Tax,
+			Discount:    item.Discount,
+			Total:       total,
+		}
+	}
+
+	if amount != 0 && amount != sum {
+		return nil, 0, fmt.Errorf("%w: amount is %d but line items total %d", ErrTotalMismatch, amount, sum)
+	}
+
+	return lineItems, sum, nil
+}
+
+func lineTotal(item dto.LineItem) (int64, error) {
+	switch {
+	case item.SKU == "":
+		return 0, fmt.Errorf("%w: sku is required", ErrInvalidInput)
+	case utf8.RuneCountInString(item.SKU) > maxSKULen:
+		return 0, fmt.Errorf("%w: sku is longer than %d characters", ErrInvalidInput, maxSKULen)
+	case utf8.RuneCountInString(item.Description) > maxDescriptionLen:
+		return 0, fmt.Errorf("%w: description is longer than %d characters", ErrInvalidInput, maxDescriptionLen)
+	case item.Quantity <= 0:
+		return 0, fmt.Errorf("%w: quantity must be positive", ErrInvalidInput)
+	case item.UnitPrice < 0 || item.Tax < 0 || item.Discount < 0:
+		return 0, fmt.Errorf("%w: unit_price, tax and discount must not be negative", ErrInvalidInput)
+	case item.UnitPrice != 0 && item.Quantity > (math.MaxInt64-item.Tax)/item.UnitPrice:
+		return 0, fmt.Errorf("%w: line total is too large", ErrInvalidInput)
+	}
+
+	gross := item.Quantity*item.UnitPrice + item.Tax
+	if item.Discount > gross {
+		return 0, fmt.Errorf("%w: discount exceeds the line total", ErrInvalidInput)
+	}
+
+	return gross - item.Discount, nil
+}
+
+// checkLineItemsTotal rejects an amount that differs from the total of the line items of a
+// transaction that has any.
+func checkLineItemsTotal(items []entity.LineItem, amount int64) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var sum int64
+	for _, item := range items {
+		sum += item.Total
+	}
+	if amount != sum {
+		return fmt.Errorf("%w: amount is %d but line items total %d", ErrTotalMismatch, amount, sum)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+type MerchantRepository interface {
+	Create(ctx context.Context, merchant *entity.Merchant) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Merchant, error)
+	FindByExternalReference(ctx context.Context, reference string) (*entity.Merchant, error)
+	FindAll(ctx context.Context, after uuid.UUID, limit int) ([]entity.Merchant, error)
+	Update(ctx context.Context, merchant *entity.Merchant) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type MerchantMapper interface {
+	ToDTO(merchant *entity.Merchant) *dto.Merchant
+	FromDTO(merchant *dto.Merchant) *entity.Merchant
+}
+
+type MerchantService struct {
+	repository MerchantRepository
+	mapper     MerchantMapper
+}
+
+func NewMerchantService(repository MerchantRepository, mapper MerchantMapper) *MerchantService {
+	return &MerchantService{repository: repository, mapper: mapper}
+}
+
+func (s *MerchantService) Create(ctx context.Context, input *dto.Merchant) (*dto.Merchant, error) {
+	if err := validateParty(input.Name, input.Email); err != nil {
+		return nil, err
+	}
+	if err := s.checkReference(ctx, uuid.Nil, input.ExternalReference); err != nil {
+		return nil, err
+	}
+
+	merchant := s.mapper.FromDTO(input)
+	merchant.ID = uuid.Nil
+	if err := s.repository.Create(ctx, merchant); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(merchant), nil
+}
+
+func (s *MerchantService) GetByID(ctx context.Context, id uuid.UUID) (*dto.Merchant, error) {
+	merchant, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(merchant), nil
+}
+
+// GetAll returns a page of merchants ordered by ID, starting after filter.After.
+func (s *MerchantService) GetAll(ctx context.Context, filter dto.PageFilter) ([]dto.Merchant, error) {
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	merchants, err := s.repository.FindAll(ctx, filter.After, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Merchant, len(merchants))
+	for i := range merchants {
+		dtos[i] = *s.mapper.ToDTO(&merchants[i])
+	}
+
+	return dtos, nil
+}
+
+func (s *MerchantService) Update(ctx context.Context, id uuid.UUID, input *dto.Merchant) (*dto.Merchant, error) {
+	if err := validateParty(input.Name, input.Email); err != nil {
+		return nil, err
+	}
+
+	merchant, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkReference(ctx, id, input.ExternalReference); err != nil {
+		return nil, err
+	}
+
+	merchant.Name = input.Name
+	merchant.ExternalReference = input.ExternalReference
+	merchant.Email = input.Email
+	if err = s.repository.Update(ctx, merchant); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(merchant), nil
+}
+
+func (s *MerchantService) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.repository.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrMerchantNotFound
+	}
+
+	return err
+}
+
+func (s *MerchantService) findByID(ctx context.Context, id uuid.UUID) (*entity.Merchant, error) {
+	merchant, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrMerchantNotFound
+	}
+
+	return merchant, err
+}
+
+// checkReference rejects an external reference already used by a merchant other than id, even a
+// deleted one.
+func (s *MerchantService) checkReference(ctx context.Context, id uuid.UUID, reference string) error {
+	if reference == "" {
+		return nil
+	}
+
+	existing, err := s.repository.FindByExternalReference(ctx, reference)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return fmt.Errorf("%w: merchant %q", ErrDuplicateReference, reference)
+	}
+
+	return nil
+}
+
+// validateParty checks the fields shared by merchants and customers.
+func validateParty(name, email string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return fmt.Errorf("%w: malformed email", ErrInvalidInput)
+		}
+	}
+
+	return nil
+}
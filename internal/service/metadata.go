@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxMetadataKeys     = 20
+	maxMetadataKeyLen   = 40
+	maxMetadataValueLen = 500
+	maxTags             = 20
+	maxTagLen           = 40
+)
+
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataKeys {
+		return fmt.Errorf("%w: metadata has more than %d keys", ErrInvalidInput, maxMetadataKeys)
+	}
+
+	for key, value := range metadata {
+		if key == "" || utf8.RuneCountInString(key) > maxMetadataKeyLen {
+			return fmt.Errorf("%w: metadata keys must have 1 to %d characters", ErrInvalidInput, maxMetadataKeyLen)
+		}
+		if utf8.RuneCountInString(value) > maxMetadataValueLen {
+			return fmt.Errorf("%w: metadata value of %q is longer than %d characters", ErrInvalidInput, key, maxMetadataValueLen)
+		}
+	}
+
+	return nil
+}
+
+// normalizeTags trims tags and drops duplicates, keeping the first occurrence of each.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLen {
+			return nil, fmt.Errorf("%w: tags must have 1 to %d characters", ErrInvalidInput, maxTagLen)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("%w: more than %d tags", ErrInvalidInput, maxTags)
+	}
+
+	return normalized, nil
+}
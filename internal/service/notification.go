@@ -2,51 +2,46 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
-const timeoutFactor = 10
+const (
+	timeoutFactor   = 10
+	eventTypeHeader = "event_type"
+	// batchTimeout replaces the one second kafka-go waits for a batch to fill by default. Publish
+	// writes one message at a time, so the relay would otherwise publish one event per second.
+	batchTimeout = 5 * time.Millisecond
+)
 
 func NewNotificationService(topic, address string) *NotificationService {
 	return &NotificationService{
-		topic:   topic,
-		address: address,
+		writer: &kafka.Writer{
+			Addr:  kafka.TCP(address),
+			Topic: topic,
+			// Hashing the key keeps every event of a transaction on one partition, in order.
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: batchTimeout,
+			WriteTimeout: timeoutFactor * time.Second,
+		},
 	}
 }
 
 type NotificationService struct {
-	topic   string
-	address string
+	writer *kafka.Writer
 }
 
-func (s *NotificationService) Publish(message any) (err error) {
-	partition := 0
-
-	messageBytes, err := json.Marshal(message)
-	if err != nil {
-		return err
-	}
-
-	conn, err := kafka.DialLeader(context.Background(), "tcp", s.address, s.topic, partition)
-	if err != nil {
-		return err
-	}
-
-	err = conn.SetWriteDeadline(time.Now().Add(timeoutFactor * time.Second))
-	if err != nil {
-		return err
-	}
-
-	_, err = conn.Write(messageBytes)
-	if err != nil {
-		return err
-	}
+// Publish writes payload to Kafka keyed by key, with eventType in the event_type header.
+func (s *NotificationService) Publish(ctx context.Context, key, eventType string, payload []byte) error {
+	return s.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(key),
+		Value:   payload,
+		Headers: []kafka.Header{{Key: eventTypeHeader, Value: []byte(eventType)}},
+	})
+}
 
-	if err := conn.Close(); err != nil {
-		return err
-	}
-	return nil
+func (s *NotificationService) Close() error {
+	return s.writer.Close()
 }
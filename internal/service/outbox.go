@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+const (
+	// outboxLockKey is the advisory lock held by the replica relaying the outbox.
+	outboxLockKey    = 5_000_001
+	outboxBatchSize  = 100
+	outboxMaxBackoff = 5 * time.Minute
+	// outboxMaxAttempts failed attempts, about an hour of retries, dead-letter an event.
+	outboxMaxAttempts = 20
+)
+
+// outboxMetrics is published at /debug/vars.
+var outboxMetrics = expvar.NewMap("outbox")
+
+type OutboxRepository interface {
+	Create(ctx context.Context, event *entity.OutboxEvent) error
+	FindPending(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEvent, error)
+	Update(ctx context.Context, event *entity.OutboxEvent) error
+	PendingStats(ctx context.Context) (int64, *time.Time, error)
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, key, eventType string, payload []byte) error
+}
+
+type Locker interface {
+	WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+type OutboxService struct {
+	repository OutboxRepository
+	publisher  Publisher
+	locker     Locker
+	retention  time.Duration
+}
+
+func NewOutboxService(
+	repository OutboxRepository, publisher Publisher, locker Locker, retention time.Duration) *OutboxService {
+	return &OutboxService{repository: repository, publisher: publisher, locker: locker, retention: retention}
+}
+
+// Enqueue records an event for aggregateID. Call it inside the transaction that writes the change.
+func (s *OutboxService) Enqueue(ctx context.Context, aggregateID uuid.UUID, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.repository.Create(ctx, &entity.OutboxEvent{
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+}
+
+// Relay publishes pending events until the outbox is drained or a batch makes no progress. Only
+// one replica relays at a time. Events of an aggregate are published in order: once one fails, the
+// rest of that aggregate waits for its retry. An event that keeps failing is dead-lettered after
+// outboxMaxAttempts attempts, which releases the events after it.
+func (s *OutboxService) Relay(ctx context.Context) error {
+	for {
+		published := 0
+		_, err := s.locker.WithAdvisoryLock(ctx, outboxLockKey, func(ctx context.Context) error {
+			var err error
+			published, err = s.relayBatch(ctx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = s.recordLag(ctx); err != nil {
+			return err
+		}
+		if published == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *OutboxService) relayBatch(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := s.repository.FindPending(ctx, now, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := map[uuid.UUID]bool{}
+	for i := range events {
+		event := &events[i]
+		if blocked[event.AggregateID] {
+			continue
+		}
+
+		err = s.publisher.Publish(ctx, event.AggregateID.String(), event.EventType, event.Payload)
+		if err != nil {
+			blocked[event.AggregateID] = true
+			event.Attempts++
+			event.LastError = err.Error()
+			event.NextAttemptAt = now.Add(outboxBackoff(event.Attempts))
+			outboxMetrics.Add("failed_total", 1)
+			if event.Attempts >= outboxMaxAttempts {
+				event.DeadAt = &now
+				outboxMetrics.Add("dead_total", 1)
+			}
+		} else {
+			event.ProcessedAt = &now
+			published++
+			outboxMetrics.Add("published_total", 1)
+		}
+
+		if err = s.repository.Update(ctx, event); err != nil {
+			return published, err
+		}
+	}
+
+	return published, nil
+}
+
+func (s *OutboxService) recordLag(ctx context.Context) error {
+	pending, oldest, err := s.repository.PendingStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	lag := new(expvar.Float)
+	if oldest != nil {
+		lag.Set(time.Since(*oldest).Seconds())
+	}
+
+	count := new(expvar.Int)
+	count.Set(pending)
+
+	outboxMetrics.Set("pending", count)
+	outboxMetrics.Set("lag_seconds", lag)
+
+	return nil
+}
+
+// PurgeProcessed removes events relayed longer ago than the retention window.
+func (s *OutboxService) PurgeProcessed(ctx context.Context) (int64, error) {
+	return s.repository.DeleteProcessedBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// outboxBackoff doubles the delay after every failed attempt, capped at outboxMaxBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := time.Second
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, outboxMaxBackoff)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	defaultSort     = "-created_at"
+)
+
+// transactionSortFields are the columns transactions may be sorted by.
+var transactionSortFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"amount":     true,
+}
+
+// cursor points just after the last row of a page. It repeats the sort so that a cursor cannot be
+// reused with a different ordering.
+type cursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	return &c, nil
+}
+
+// parseSort splits a sort parameter such as -created_at into its field and direction.
+func parseSort(sort string) (field string, descending bool, err error) {
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	field = strings.TrimPrefix(sort, "-")
+	if !transactionSortFields[field] {
+		return "", false, fmt.Errorf("%w: cannot sort by %q", ErrInvalidInput, field)
+	}
+
+	return field, strings.HasPrefix(sort, "-"), nil
+}
+
+// parseCursorValue converts a cursor value back into the type of its sort field.
+func parseCursorValue(field, value string) (any, error) {
+	if field == "amount" {
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+		}
+		return amount, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+	return t, nil
+}
+
+func pageSize(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return defaultPageSize, nil
+	case limit < 0 || limit > maxPageSize:
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxPageSize)
+	default:
+		return limit, nil
+	}
+}
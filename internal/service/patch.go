@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/jsonpatch"
+)
+
+const (
+	// MergePatch is an RFC 7396 JSON Merge Patch.
+	MergePatch = "merge"
+	// JSONPatch is an RFC 6902 JSON Patch.
+	JSONPatch = "json"
+)
+
+// patchableFields are the fields of a transaction a patch can change. The others are read only.
+var patchableFields = map[string]bool{"status": true, "amount": true, "metadata": true, "tags": true}
+
+// requiredFields are the patchable fields a patch cannot remove.
+var requiredFields = []string{"status", "amount"}
+
+// Patch applies a patch in format to the JSON representation of a transaction and updates it to
+// the result as Update does. Only the status, amount, metadata and tags can change, and removing
+// the metadata or tags clears them.
+func (s *TransactionService) Patch(ctx context.Context, id uuid.UUID, format string, patch []byte) (*dto.Transaction, error) {
+	if format != MergePatch && format != JSONPatch {
+		return nil, fmt.Errorf("%w: unknown patch format %q", ErrInvalidInput, format)
+	}
+
+	return s.update(ctx, id, func(before *dto.Transaction) (*dto.Transaction, error) {
+		return applyPatch(before, format, patch)
+	})
+}
+
+// applyPatch returns the update input that results from applying patch to transaction.
+func applyPatch(transaction *dto.Transaction, format string, patch []byte) (*dto.Transaction, error) {
+	document, err := json.Marshal(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	if format == MergePatch {
+		patched, err = jsonpatch.Merge(document, patch)
+	} else {
+		patched, err = jsonpatch.Apply(document, patch)
+	}
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		return nil, fmt.Errorf("%w: %s", ErrPatchConflict, err)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInput, err)
+	}
+
+	if err = checkPatchedFields(document, patched); err != nil {
+		return nil, err
+	}
+
+	var input dto.Transaction
+	if err = json.Unmarshal(patched, &input); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: %s must be of type %s", ErrInvalidInput, typeErr.Field, typeErr.Type)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInput, err)
+	}
+	if input.Metadata == nil {
+		input.Metadata = map[string]string{}
+	}
+	if input.Tags == nil {
+		input.Tags = []string{}
+	}
+
+	return &input, nil
+}
+
+// checkPatchedFields rejects a patched document that is not an object, changes a read only field
+// or removes a required one.
+func checkPatchedFields(document, patched []byte) error {
+	var before, after map[string]any
+	if err := json.Unmarshal(document, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil || after == nil {
+		return fmt.Errorf("%w: a patched transaction must be an object", ErrInvalidInput)
+	}
+
+	for _, fields := range []map[string]any{before, after} {
+		for field := range fields {
+			if !patchableFields[field] && !reflect.DeepEqual(before[field], after[field]) {
+				return fmt.Errorf("%w: %s is read only", ErrInvalidInput, field)
+			}
+		}
+	}
+	for _, field := range requiredFields {
+		if after[field] == nil {
+			return fmt.Errorf("%w: %s cannot be removed", ErrInvalidInput, field)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	FeeCharge = "fee"
+	TaxCharge = "tax"
+
+	percentageRule = "percentage"
+	fixedRule      = "fixed"
+	tieredRule     = "tiered"
+
+	maxRateBps = 10000
+	maxTiers   = 20
+)
+
+type PricingRepository interface {
+	Create(ctx context.Context, rule *entity.PricingRule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.PricingRule, error)
+	FindAll(ctx context.Context) ([]entity.PricingRule, error)
+	FindApplicable(ctx context.Context, currency string, merchantID *uuid.UUID) ([]entity.PricingRule, error)
+	Update(ctx context.Context, rule *entity.PricingRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type PricingMapper interface {
+	ToDTO(rule *entity.PricingRule) *dto.PricingRule
+	FromDTO(rule *dto.PricingRule) *entity.PricingRule
+}
+
+// PricingService manages the fee and tax rules and charges them on transactions.
+type PricingService struct {
+	repository PricingRepository
+	merchants  MerchantRepository
+	mapper     PricingMapper
+}
+
+func NewPricingService(repository PricingRepository, merchants MerchantRepository, mapper PricingMapper) *PricingService {
+	return &PricingService{repository: repository, merchants: merchants, mapper: mapper}
+}
+
+func (s *PricingService) Create(ctx context.Context, input *dto.PricingRule) (*dto.PricingRule, error) {
+	rule := s.mapper.FromDTO(input)
+	rule.ID = uuid.Nil
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(rule), nil
+}
+
+func (s *PricingService) GetByID(ctx context.Context, id uuid.UUID) (*dto.PricingRule, error) {
+	rule, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(rule), nil
+}
+
+func (s *PricingService) GetAll(ctx context.Context) ([]dto.PricingRule, error) {
+	rules, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.PricingRule, len(rules))
+	for i := range rules {
+		dtos[i] = *s.mapper.ToDTO(&rules[i])
+	}
+
+	return dtos, nil
+}
+
+// Update replaces a rule. Transactions priced by it keep their charges until their amount changes.
+func (s *PricingService) Update(ctx context.Context, id uuid.UUID, input *dto.PricingRule) (*dto.PricingRule, error) {
+	existing, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := s.mapper.FromDTO(input)
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err = s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err = s.repository.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(rule), nil
+}
+
+func (s *PricingService) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.repository.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPricingRuleNotFound
+	}
+
+	return err
+}
+
+// Price charges the applicable rules on the amount of transaction and stores the result in its
+// breakdown. For each kind, the rules of the merchant of the transaction replace the general ones.
+// Nothing is charged on a zero amount.
+func (s *PricingService) Price(ctx context.Context, transaction *entity.Transaction) error {
+	var rules []entity.PricingRule
+	if transaction.Amount > 0 {
+		var err error
+		if rules, err = s.repository.FindApplicable(ctx, transaction.Currency, transaction.MerchantID); err != nil {
+			return err
+		}
+	}
+
+	merchantKinds := map[string]bool{}
+	for i := range rules {
+		if rules[i].MerchantID != nil {
+			merchantKinds[rules[i].Kind] = true
+		}
+	}
+
+	charges := entity.Charges{}
+	var fees, tax int64
+	for i := range rules {
+		rule := &rules[i]
+		if merchantKinds[rule.Kind] && rule.MerchantID == nil {
+			continue
+		}
+
+		amount := chargeAmount(rule, transaction.Amount)
+		if amount == 0 {
+			continue
+		}
+		if rule.Kind == TaxCharge {
+			tax += amount
+		} else {
+			fees += amount
+		}
+		charges = append(charges, entity.Charge{RuleID: rule.ID, Name: rule.Name, Kind: rule.Kind, Amount: amount})
+	}
+
+	if fees+tax > transaction.Amount {
+		return fmt.Errorf("%w: %d of fees and %d of tax on %d", ErrChargesExceedAmount, fees, tax, transaction.Amount)
+	}
+
+	transaction.FeeAmount = fees
+	transaction.TaxAmount = tax
+	transaction.NetAmount = transaction.Amount - fees - tax
+	transaction.Charges = charges
+
+	return nil
+}
+
+// chargeAmount is what rule charges on amount. Percentages are rounded half up to the minor unit.
+func chargeAmount(rule *entity.PricingRule, amount int64) int64 {
+	switch rule.Type {
+	case percentageRule:
+		return applyRate(amount, rule.RateBps)
+	case fixedRule:
+		return rule.FixedAmount
+	case tieredRule:
+		for _, tier := range rule.Tiers {
+			if tier.UpTo == 0 || amount <= tier.UpTo {
+				return applyRate(amount, tier.RateBps) + tier.FixedAmount
+			}
+		}
+	}
+
+	return 0
+}
+
+// applyRate computes rateBps basis points of amount without overflowing.
+func applyRate(amount, rateBps int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(amount), big.NewInt(rateBps))
+	product.Add(product, big.NewInt(maxRateBps/2))
+
+	return product.Quo(product, big.NewInt(maxRateBps)).Int64()
+}
+
+// validate normalizes rule and rejects rules that cannot be evaluated.
+func (s *PricingService) validate(ctx context.Context, rule *entity.PricingRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Currency = currency.Normalize(rule.Currency)
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if rule.Kind != FeeCharge && rule.Kind != TaxCharge {
+		return fmt.Errorf("%w: kind must be %s or %s", ErrInvalidInput, FeeCharge, TaxCharge)
+	}
+	if rule.Currency != "" {
+		if err := currency.Validate(rule.Currency); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+	}
+	if err := validateRuleAmounts(rule); err != nil {
+		return err
+	}
+
+	if rule.MerchantID != nil {
+		_, err := s.merchants.FindByID(ctx, *rule.MerchantID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownMerchant, rule.MerchantID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRuleAmounts checks the rates, fixed amounts and tiers of rule against its type, clearing
+// the fields the type does not use. Fixed amounts are in minor units, so they need a currency.
+func validateRuleAmounts(rule *entity.PricingRule) error {
+	switch rule.Type {
+	case percentageRule:
+		rule.FixedAmount, rule.Tiers = 0, nil
+		return validateRate(rule.RateBps)
+	case fixedRule:
+		rule.RateBps, rule.Tiers = 0, nil
+		if rule.Currency == "" {
+			return fmt.Errorf("%w: fixed rules need a currency", ErrInvalidInput)
+		}
+		if rule.FixedAmount < 0 {
+			return fmt.Errorf("%w: fixed_amount must not be negative", ErrInvalidInput)
+		}
+		return nil
+	case tieredRule:
+		rule.RateBps, rule.FixedAmount = 0, 0
+		if rule.Currency == "" {
+			return fmt.Errorf("%w: tiered rules need a currency", ErrInvalidInput)
+		}
+		return validateTiers(rule.Tiers)
+	default:
+		return fmt.Errorf("%w: type must be %s, %s or %s", ErrInvalidInput, percentageRule, fixedRule, tieredRule)
+	}
+}
+
+// validateTiers requires tiers in increasing order of UpTo, only the last of which may be unbounded.
+func validateTiers(tiers entity.PricingTiers) error {
+	if len(tiers) == 0 || len(tiers) > maxTiers {
+		return fmt.Errorf("%w: tiered rules need 1 to %d tiers", ErrInvalidInput, maxTiers)
+	}
+
+	var previous int64
+	for i, tier := range tiers {
+		if err := validateRate(tier.RateBps); err != nil {
+			return err
+		}
+		if tier.FixedAmount < 0 {
+			return fmt.Errorf("%w: fixed_amount of tier %d must not be negative", ErrInvalidInput, i)
+		}
+		if tier.UpTo == 0 && i == len(tiers)-1 {
+			continue
+		}
+		if tier.UpTo <= previous {
+			return fmt.Errorf("%w: up_to of tier %d must be greater than %d", ErrInvalidInput, i, previous)
+		}
+		previous = tier.UpTo
+	}
+
+	return nil
+}
+
+func validateRate(rateBps int64) error {
+	if rateBps < 0 || rateBps > maxRateBps {
+		return fmt.Errorf("%w: rate_bps must be between 0 and %d", ErrInvalidInput, maxRateBps)
+	}
+
+	return nil
+}
+
+func (s *PricingService) findByID(ctx context.Context, id uuid.UUID) (*entity.PricingRule, error) {
+	rule, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPricingRuleNotFound
+	}
+
+	return rule, err
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+func TestChargeAmount(t *testing.T) {
+	tiers := entity.PricingTiers{
+		{UpTo: 1000, RateBps: 100, FixedAmount: 10},
+		{UpTo: 5000, RateBps: 50},
+		{RateBps: 25},
+	}
+
+	tests := []struct {
+		name   string
+		rule   entity.PricingRule
+		amount int64
+		want   int64
+	}{
+		{"percentage", entity.PricingRule{Type: percentageRule, RateBps: 290}, 10000, 290},
+		{"percentage rounds half up", entity.PricingRule{Type: percentageRule, RateBps: 1}, 5000, 1},
+		{"percentage rounds down below half", entity.PricingRule{Type: percentageRule, RateBps: 1}, 4999, 0},
+		{"percentage of fractions", entity.PricingRule{Type: percentageRule, RateBps: 250}, 1999, 50},
+		{"zero rate", entity.PricingRule{Type: percentageRule}, 10000, 0},
+		{"whole amount", entity.PricingRule{Type: percentageRule, RateBps: maxRateBps}, 12345, 12345},
+		{"largest amount", entity.PricingRule{Type: percentageRule, RateBps: maxRateBps}, math.MaxInt64, math.MaxInt64},
+		{"fixed", entity.PricingRule{Type: fixedRule, FixedAmount: 30}, 10000, 30},
+		{"fixed above the amount", entity.PricingRule{Type: fixedRule, FixedAmount: 30}, 10, 30},
+		{"first tier bound", entity.PricingRule{Type: tieredRule, Tiers: tiers}, 1000, 20},
+		{"second tier", entity.PricingRule{Type: tieredRule, Tiers: tiers}, 1001, 5},
+		{"second tier bound", entity.PricingRule{Type: tieredRule, Tiers: tiers}, 5000, 25},
+		{"unbounded tier", entity.PricingRule{Type: tieredRule, Tiers: tiers}, 100000, 250},
+		{"above bounded tiers", entity.PricingRule{Type: tieredRule, Tiers: tiers[:2]}, 5001, 0},
+		{"unknown type", entity.PricingRule{Type: "other", RateBps: 100}, 10000, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := chargeAmount(&test.rule, test.amount); got != test.want {
+				t.Fatalf("chargeAmount() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+// fakePricingRules returns its rules as the applicable ones.
+type fakePricingRules struct {
+	PricingRepository
+	rules []entity.PricingRule
+	calls int
+}
+
+func (r *fakePricingRules) FindApplicable(context.Context, string, *uuid.UUID) ([]entity.PricingRule, error) {
+	r.calls++
+	return r.rules, nil
+}
+
+func TestPrice(t *testing.T) {
+	merchantID := uuid.New()
+	fee := func(ruleType string, rateBps, fixed int64, merchantID *uuid.UUID) entity.PricingRule {
+		return entity.PricingRule{ID: uuid.New(), Kind: FeeCharge, Type: ruleType, RateBps: rateBps, FixedAmount: fixed, MerchantID: merchantID}
+	}
+	tax := func(rateBps int64) entity.PricingRule {
+		return entity.PricingRule{ID: uuid.New(), Kind: TaxCharge, Type: percentageRule, RateBps: rateBps}
+	}
+
+	tests := []struct {
+		name    string
+		rules   []entity.PricingRule
+		amount  int64
+		fees    int64
+		tax     int64
+		charges int
+		err     error
+	}{
+		{"no rules", nil, 1000, 0, 0, 0, nil},
+		{"fees and tax", []entity.PricingRule{fee(percentageRule, 290, 0, nil), fee(fixedRule, 0, 30, nil), tax(1000)},
+			10000, 320, 1000, 3, nil},
+		{"merchant fees replace general fees", []entity.PricingRule{fee(percentageRule, 290, 0, nil),
+			fee(fixedRule, 0, 30, &merchantID), tax(1000)}, 10000, 30, 1000, 2, nil},
+		{"zero charges are left out", []entity.PricingRule{fee(percentageRule, 1, 0, nil), tax(1000)}, 4000, 0, 400, 1, nil},
+		{"charges up to the amount", []entity.PricingRule{fee(fixedRule, 0, 60, nil), tax(4000)}, 100, 60, 40, 2, nil},
+		{"charges above the amount", []entity.PricingRule{fee(fixedRule, 0, 60, nil), tax(4100)}, 100, 0, 0, 0,
+			ErrChargesExceedAmount},
+		{"nothing on a zero amount", []entity.PricingRule{fee(fixedRule, 0, 30, nil)}, 0, 0, 0, 0, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules := &fakePricingRules{rules: test.rules}
+			service := NewPricingService(rules, nil, nil)
+			transaction := &entity.Transaction{Amount: test.amount, Currency: "USD", MerchantID: &merchantID}
+
+			err := service.Price(context.Background(), transaction)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("Price() error = %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Price() error = %v", err)
+			}
+
+			if transaction.FeeAmount != test.fees || transaction.TaxAmount != test.tax {
+				t.Fatalf("fees %d and tax %d, want %d and %d", transaction.FeeAmount, transaction.TaxAmount, test.fees, test.tax)
+			}
+			if transaction.NetAmount != test.amount-test.fees-test.tax {
+				t.Fatalf("net %d, want %d", transaction.NetAmount, test.amount-test.fees-test.tax)
+			}
+			if len(transaction.Charges) != test.charges {
+				t.Fatalf("%d charges, want %d", len(transaction.Charges), test.charges)
+			}
+			if test.amount == 0 && rules.calls != 0 {
+				t.Fatalf("rules were loaded for a zero amount")
+			}
+		})
+	}
+}
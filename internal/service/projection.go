@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	// projectionLockKey is the advisory lock held by the replica projecting into Mongo.
+	projectionLockKey   = 5_000_002
+	projectionBatchSize = 100
+)
+
+// projectionMetrics is published at /debug/vars.
+var projectionMetrics = expvar.NewMap("projection")
+
+type ProjectionRepository interface {
+	Mark(ctx context.Context, ids ...uuid.UUID) error
+	MarkAll(ctx context.Context) (int64, error)
+	FindPending(ctx context.Context, now time.Time, limit int) ([]entity.ProjectionTask, error)
+	Update(ctx context.Context, task *entity.ProjectionTask) error
+	Complete(ctx context.Context, task *entity.ProjectionTask) error
+	Count(ctx context.Context) (int64, error)
+}
+
+type ProjectionSource interface {
+	FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+}
+
+type DocumentRepository interface {
+	Replace(ctx context.Context, transaction *entity.Transaction) (bool, error)
+	Delete(ctx context.Context, id uuid.UUID) (bool, error)
+	FindIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+// ProjectionService keeps the Mongo transactions collection a read model of Postgres. Writes only
+// queue a projection task; the task is cleared once Mongo holds the current Postgres row.
+type ProjectionService struct {
+	locker    Locker
+	tasks     ProjectionRepository
+	source    ProjectionSource
+	documents DocumentRepository
+}
+
+func NewProjectionService(
+	locker Locker, tasks ProjectionRepository, source ProjectionSource, documents DocumentRepository) *ProjectionService {
+	return &ProjectionService{locker: locker, tasks: tasks, source: source, documents: documents}
+}
+
+// Project works through due projection tasks until none are left. Only one replica projects at a
+// time; failed tasks are retried with backoff.
+func (s *ProjectionService) Project(ctx context.Context) error {
+	for {
+		processed := 0
+		_, err := s.locker.WithAdvisoryLock(ctx, projectionLockKey, func(ctx context.Context) error {
+			var err error
+			processed, err = s.projectBatch(ctx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		pending, err := s.tasks.Count(ctx)
+		if err != nil {
+			return err
+		}
+		count := new(expvar.Int)
+		count.Set(pending)
+		projectionMetrics.Set("pending", count)
+
+		if processed == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *ProjectionService) projectBatch(ctx context.Context) (int, error) {
+	now := time.Now()
+	tasks, err := s.tasks.FindPending(ctx, now, projectionBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range tasks {
+		task := &tasks[i]
+		if _, err = s.Repair(ctx, task.TransactionID); err != nil {
+			task.Attempts++
+			task.LastError = err.Error()
+			task.NextAttemptAt = now.Add(outboxBackoff(task.Attempts))
+			projectionMetrics.Add("failed_total", 1)
+			if err = s.tasks.Update(ctx, task); err != nil {
+				return processed, err
+			}
+			continue
+		}
+
+		if err = s.tasks.Complete(ctx, task); err != nil {
+			return processed, err
+		}
+		processed++
+		projectionMetrics.Add("projected_total", 1)
+	}
+
+	return processed, nil
+}
+
+// Repair rewrites the Mongo document of id from Postgres, removing it when the row no longer exists,
+// and reports whether Mongo changed. A document of the same or an older version is replaced, so a
+// drifted one is fixed too, while a newer one written by a racing projection is kept.
+func (s *ProjectionService) Repair(ctx context.Context, id uuid.UUID) (bool, error) {
+	transaction, err := s.source.FindByIDWithDeleted(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.documents.Delete(ctx, id)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return s.documents.Replace(ctx, transaction)
+}
+
+// ResyncAll queues every transaction in Postgres and every document in Mongo, so that drifted
+// documents are rewritten and orphaned ones removed by the projector.
+func (s *ProjectionService) ResyncAll(ctx context.Context) (int64, error) {
+	marked, err := s.tasks.MarkAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	after := uuid.Nil
+	for {
+		var ids []uuid.UUID
+		ids, err = s.documents.FindIDs(ctx, after, projectionBatchSize)
+		if err != nil {
+			return marked, err
+		}
+		if len(ids) == 0 {
+			return marked, nil
+		}
+
+		if err = s.tasks.Mark(ctx, ids...); err != nil {
+			return marked, err
+		}
+		marked += int64(len(ids))
+		after = ids[len(ids)-1]
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+const (
+	TransactionPurgedEvent = "transaction.purged"
+
+	purgeBatchSize = 100
+)
+
+type PurgeRepository interface {
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	Purge(ctx context.Context, id uuid.UUID, before time.Time) (*entity.Transaction, error)
+}
+
+// PurgeService permanently removes the transactions deleted longer ago than a retention period.
+type PurgeService struct {
+	transactor Transactor
+	repository PurgeRepository
+	outbox     Outbox
+	mapper     TransactionMapper
+	retention  time.Duration
+}
+
+func NewPurgeService(
+	transactor Transactor,
+	repository PurgeRepository,
+	outbox Outbox,
+	mapper TransactionMapper,
+	retention time.Duration) *PurgeService {
+	return &PurgeService{
+		transactor: transactor,
+		repository: repository,
+		outbox:     outbox,
+		mapper:     mapper,
+		retention:  retention,
+	}
+}
+
+// Purge permanently deletes the transactions deleted before the retention period, publishing
+// transaction.purged with the last state of each. Their documents leave Mongo through the
+// projection. Each batch commits on its own, so a failed purge keeps what it purged so far.
+func (s *PurgeService) Purge(ctx context.Context, filter dto.PurgeFilter) (*dto.PurgeResult, error) {
+	retention := s.retention
+	if filter.Retention != "" {
+		var err error
+		if retention, err = time.ParseDuration(filter.Retention); err != nil {
+			return nil, fmt.Errorf("%w: retention %q is not a duration", ErrInvalidInput, filter.Retention)
+		}
+	}
+	if retention <= 0 {
+		return nil, fmt.Errorf("%w: retention must be positive", ErrInvalidInput)
+	}
+
+	result := &dto.PurgeResult{DeletedBefore: time.Now().Add(-retention)}
+	for {
+		found, err := s.purgeBatch(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+		if found < purgeBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// purgeBatch purges a batch of the transactions deleted before the cutoff of result, counting
+// them in it, and reports how many it found.
+func (s *PurgeService) purgeBatch(ctx context.Context, result *dto.PurgeResult) (int, error) {
+	found, purged := 0, int64(0)
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		ids, err := s.repository.FindDeletedBefore(ctx, result.DeletedBefore, purgeBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			transaction, err := s.repository.Purge(ctx, id, result.DeletedBefore)
+			if err != nil {
+				return err
+			}
+			if transaction == nil {
+				continue
+			}
+
+			if err = s.outbox.Enqueue(ctx, id, TransactionPurgedEvent, s.mapper.ToDTO(transaction)); err != nil {
+				return err
+			}
+			purged++
+		}
+		found = len(ids)
+
+		return nil
+	})
+	if err == nil {
+		result.Purged += purged
+	}
+
+	return found, err
+}
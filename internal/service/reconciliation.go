@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ReconciliationSource interface {
+	FindBatch(ctx context.Context, after uuid.UUID, limit int) ([]entity.Transaction, error)
+	FindExistingIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+}
+
+type ReconciliationDocuments interface {
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.Transaction, error)
+	FindIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+type Repairer interface {
+	Repair(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// ReconciliationService compares Postgres, the source of truth, with the Mongo read model.
+type ReconciliationService struct {
+	source    ReconciliationSource
+	documents ReconciliationDocuments
+	repairer  Repairer
+}
+
+func NewReconciliationService(
+	source ReconciliationSource, documents ReconciliationDocuments, repairer Repairer) *ReconciliationService {
+	return &ReconciliationService{source: source, documents: documents, repairer: repairer}
+}
+
+// Reconcile scans both stores in batches of batchSize and passes every discrepancy to report.
+// With repair, each discrepancy is fixed by rewriting Mongo from Postgres before it is reported.
+func (s *ReconciliationService) Reconcile(
+	ctx context.Context, batchSize int, repair bool, report func(dto.Discrepancy) error) (*dto.ReconciliationSummary, error) {
+	summary := &dto.ReconciliationSummary{}
+	emit := func(discrepancy dto.Discrepancy) error {
+		switch discrepancy.Kind {
+		case dto.DiscrepancyMissing:
+			summary.Missing++
+		case dto.DiscrepancyExtra:
+			summary.Extra++
+		default:
+			summary.Mismatched++
+		}
+
+		if repair {
+			repaired, err := s.repairer.Repair(ctx, discrepancy.TransactionID)
+			if err != nil {
+				return err
+			}
+			if repaired {
+				discrepancy.Repaired = true
+				summary.Repaired++
+			}
+		}
+
+		return report(discrepancy)
+	}
+
+	if err := s.compareRows(ctx, batchSize, summary, emit); err != nil {
+		return summary, err
+	}
+	if err := s.findExtraDocuments(ctx, batchSize, summary, emit); err != nil {
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+// compareRows reports rows without a document and documents that differ from their row.
+func (s *ReconciliationService) compareRows(
+	ctx context.Context, batchSize int, summary *dto.ReconciliationSummary, emit func(dto.Discrepancy) error) error {
+	after := uuid.Nil
+	for {
+		rows, err := s.source.FindBatch(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		summary.ScannedRows += int64(len(rows))
+
+		ids := make([]uuid.UUID, len(rows))
+		for i := range rows {
+			ids[i] = rows[i].ID
+		}
+
+		documents, err := s.documents.FindByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]*entity.Transaction, len(documents))
+		for i := range documents {
+			byID[documents[i].ID] = &documents[i]
+		}
+
+		for i := range rows {
+			document, ok := byID[rows[i].ID]
+			if !ok {
+				err = emit(dto.Discrepancy{TransactionID: rows[i].ID, Kind: dto.DiscrepancyMissing})
+			} else if fields := diffDocument(&rows[i], document); len(fields) > 0 {
+				err = emit(dto.Discrepancy{TransactionID: rows[i].ID, Kind: dto.DiscrepancyMismatched, Fields: fields})
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		after = ids[len(ids)-1]
+	}
+}
+
+// findExtraDocuments reports documents without a row.
+func (s *ReconciliationService) findExtraDocuments(
+	ctx context.Context, batchSize int, summary *dto.ReconciliationSummary, emit func(dto.Discrepancy) error) error {
+	after := uuid.Nil
+	for {
+		ids, err := s.documents.FindIDs(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		summary.ScannedDocuments += int64(len(ids))
+
+		existing, err := s.source.FindExistingIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		found := make(map[uuid.UUID]bool, len(existing))
+		for _, id := range existing {
+			found[id] = true
+		}
+
+		for _, id := range ids {
+			if found[id] {
+				continue
+			}
+			if err = emit(dto.Discrepancy{TransactionID: id, Kind: dto.DiscrepancyExtra}); err != nil {
+				return err
+			}
+		}
+
+		after = ids[len(ids)-1]
+	}
+}
+
+// diffDocument lists the fields in which document differs from row. Mongo keeps timestamps with
+// millisecond precision, so they are compared at that precision.
+func diffDocument(row, document *entity.Transaction) []string {
+	checks := []struct {
+		field string
+		same  bool
+	}{
+		{"amount", row.Amount == document.Amount},
+		{"refunded_amount", row.RefundedAmount == document.RefundedAmount},
+		{"charged_back_amount", row.ChargedBackAmount == document.ChargedBackAmount},
+		{"authorization", sameAuthorization(row, document)},
+		{"merchant_id", sameID(row.MerchantID, document.MerchantID)},
+		{"customer_id", sameID(row.CustomerID, document.CustomerID)},
+		{"metadata", maps.Equal(row.Metadata, document.Metadata)},
+		{"tags", slices.Equal(row.Tags, document.Tags)},
+		{"line_items", slices.EqualFunc(row.LineItems, document.LineItems, sameLineItem)},
+		{"breakdown", sameBreakdown(row, document)},
+		{"settlement", sameSettlement(row, document)},
+		{"split", sameSplit(row, document)},
+		{"currency", row.Currency == document.Currency},
+		{"status", row.Status.Name == document.Status.Name},
+		{"is_deleted", row.IsDeleted == document.IsDeleted},
+		{"deleted_at", sameDeletion(row.DeletedAt, document.DeletedAt)},
+		{"created_at", sameMillisecond(row.CreatedAt, document.CreatedAt)},
+		{"updated_at", sameMillisecond(row.UpdatedAt, document.UpdatedAt)},
+		{"version", row.Version == document.Version},
+	}
+
+	var fields []string
+	for _, check := range checks {
+		if !check.same {
+			fields = append(fields, check.field)
+		}
+	}
+
+	return fields
+}
+
+func sameAuthorization(row, document *entity.Transaction) bool {
+	return row.AuthorizedAmount == document.AuthorizedAmount && row.CapturedAmount == document.CapturedAmount &&
+		row.VoidedAmount == document.VoidedAmount
+}
+
+func sameDeletion(a, b gorm.DeletedAt) bool {
+	return a.Valid == b.Valid && sameMillisecond(a.Time, b.Time)
+}
+
+func sameBreakdown(row, document *entity.Transaction) bool {
+	return row.FeeAmount == document.FeeAmount && row.TaxAmount == document.TaxAmount &&
+		row.NetAmount == document.NetAmount && slices.Equal(row.Charges, document.Charges)
+}
+
+func sameSettlement(row, document *entity.Transaction) bool {
+	return row.SettlementCurrency == document.SettlementCurrency && row.SettlementAmount == document.SettlementAmount &&
+		row.FxRate == document.FxRate
+}
+
+func sameSplit(row, document *entity.Transaction) bool {
+	return row.SplitType == document.SplitType && sameID(row.ParentID, document.ParentID) &&
+		row.Installment == document.Installment && sameOptionalMillisecond(row.DueAt, document.DueAt)
+}
+
+func sameLineItem(a, b entity.LineItem) bool {
+	return a.SKU == b.SKU && a.Description == b.Description && a.Quantity == b.Quantity &&
+		a.UnitPrice == b.UnitPrice && a.Tax == b.Tax && a.Discount == b.Discount && a.Total == b.Total
+}
+
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func sameMillisecond(a, b time.Time) bool {
+	return a.Truncate(time.Millisecond).Equal(b.Truncate(time.Millisecond))
+}
+
+func sameOptionalMillisecond(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return sameMillisecond(*a, *b)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+
+	RefundCreatedEvent   = "refund.created"
+	RefundSucceededEvent = "refund.succeeded"
+	RefundFailedEvent    = "refund.failed"
+)
+
+// refundTransitions is the refund status graph. Succeeded and failed refunds are final.
+var refundTransitions = map[string][]string{
+	RefundPending:   {RefundSucceeded, RefundFailed},
+	RefundSucceeded: nil,
+	RefundFailed:    nil,
+}
+
+// refundableStatuses are the transaction statuses that accept new refunds.
+var refundableStatuses = map[string]bool{
+	completedStatus:         true,
+	partiallyRefundedStatus: true,
+}
+
+type RefundRepository interface {
+	Create(ctx context.Context, refund *entity.Refund) error
+	FindByID(ctx context.Context, transactionID, id uuid.UUID) (*entity.Refund, error)
+	FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]entity.Refund, error)
+	Update(ctx context.Context, refund *entity.Refund) error
+	SumAmount(ctx context.Context, transactionID uuid.UUID, statuses []string) (int64, error)
+}
+
+type RefundMapper interface {
+	ToDTO(refund *entity.Refund) *dto.Refund
+}
+
+// RefundTransactions loads and updates the transaction a refund belongs to. It is implemented by
+// TransactionService.
+type RefundTransactions interface {
+	findByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	findByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	applyRefund(ctx context.Context, transaction *entity.Transaction, amount int64) error
+}
+
+type RefundService struct {
+	transactor   Transactor
+	repository   RefundRepository
+	disputes     ClaimedAmounts
+	transactions RefundTransactions
+	outbox       Outbox
+	mapper       RefundMapper
+}
+
+func NewRefundService(
+	transactor Transactor,
+	repository RefundRepository,
+	disputes ClaimedAmounts,
+	transactions RefundTransactions,
+	outbox Outbox,
+	mapper RefundMapper) *RefundService {
+	return &RefundService{
+		transactor:   transactor,
+		repository:   repository,
+		disputes:     disputes,
+		transactions: transactions,
+		outbox:       outbox,
+		mapper:       mapper,
+	}
+}
+
+// Create requests a pending refund of amount on a completed or partially refunded transaction.
+// Pending and succeeded refunds and open disputes together may not exceed the amount of the
+// transaction, less what was charged back.
+func (s *RefundService) Create(
+	ctx context.Context, transactionID uuid.UUID, amount int64, currencyCode, reason string) (*dto.Refund, error) {
+	var refund *entity.Refund
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		transaction, err := s.transactions.findByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		if !refundableStatuses[transaction.Status.Name] {
+			return fmt.Errorf("%w: transaction is %q", ErrNotRefundable, transaction.Status.Name)
+		}
+		if transaction.SplitType != "" {
+			return fmt.Errorf("%w: refund the children of a split transaction", ErrNotRefundable)
+		}
+		currencyCode = currency.Normalize(currencyCode)
+		if currencyCode != "" && currencyCode != transaction.Currency {
+			return fmt.Errorf("%w: refund currency must be %s", ErrInvalidInput, transaction.Currency)
+		}
+		if amount <= 0 {
+			return fmt.Errorf("%w: amount must be positive", ErrInvalidInput)
+		}
+
+		available, err := unclaimedAmount(ctx, transaction, s.repository, s.disputes)
+		if err != nil {
+			return err
+		}
+		if amount > available {
+			return fmt.Errorf("%w: only %d is neither refunded nor disputed", ErrRefundExceedsAmount, available)
+		}
+
+		refund = &entity.Refund{
+			TransactionID: transactionID,
+			Amount:        amount,
+			Currency:      transaction.Currency,
+			Status:        RefundPending,
+			Reason:        reason,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		if err = s.repository.Create(ctx, refund); err != nil {
+			return err
+		}
+
+		return s.outbox.Enqueue(ctx, transactionID, RefundCreatedEvent, s.mapper.ToDTO(refund))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(refund), nil
+}
+
+// UpdateStatus settles a pending refund as succeeded or failed. A succeeded refund is added to the
+// refunded amount of its transaction, which moves to partially_refunded or refunded.
+func (s *RefundService) UpdateStatus(ctx context.Context, transactionID, id uuid.UUID, status string) (*dto.Refund, error) {
+	var refund *entity.Refund
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		transaction, err := s.transactions.findByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		refund, err = s.findByID(ctx, transactionID, id)
+		if err != nil {
+			return err
+		}
+
+		if err = transitionRefund(refund, status); err != nil {
+			return err
+		}
+		refund.UpdatedAt = time.Now()
+
+		if err = s.repository.Update(ctx, refund); err != nil {
+			return err
+		}
+
+		eventType := RefundFailedEvent
+		if refund.Status == RefundSucceeded {
+			eventType = RefundSucceededEvent
+			if err = s.transactions.applyRefund(ctx, transaction, refund.Amount); err != nil {
+				return err
+			}
+		}
+
+		return s.outbox.Enqueue(ctx, transactionID, eventType, s.mapper.ToDTO(refund))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(refund), nil
+}
+
+func (s *RefundService) GetByID(ctx context.Context, transactionID, id uuid.UUID) (*dto.Refund, error) {
+	refund, err := s.findByID(ctx, transactionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(refund), nil
+}
+
+// GetByTransactionID returns the refunds of a transaction, oldest first.
+func (s *RefundService) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]dto.Refund, error) {
+	if _, err := s.transactions.findByID(ctx, transactionID); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.repository.FindByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Refund, len(refunds))
+	for i := range refunds {
+		dtos[i] = *s.mapper.ToDTO(&refunds[i])
+	}
+
+	return dtos, nil
+}
+
+func (s *RefundService) findByID(ctx context.Context, transactionID, id uuid.UUID) (*entity.Refund, error) {
+	refund, err := s.repository.FindByID(ctx, transactionID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRefundNotFound
+	}
+
+	return refund, err
+}
+
+// transitionRefund moves refund to status if the refund status graph allows it.
+func transitionRefund(refund *entity.Refund, status string) error {
+	if _, ok := refundTransitions[status]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, status)
+	}
+
+	for _, next := range refundTransitions[refund.Status] {
+		if next == status {
+			refund.Status = status
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: refund %q to %q", ErrInvalidTransition, refund.Status, status)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+	"github.com/the-great-checkout/transactions-crud/internal/requestctx"
+)
+
+const (
+	RiskApproved = "approved"
+	RiskDeclined = "declined"
+
+	TransactionApprovedEvent = "transaction.approved"
+	TransactionDeclinedEvent = "transaction.declined"
+)
+
+type RiskAssessments interface {
+	FindAssessment(ctx context.Context, transactionID uuid.UUID) (*entity.RiskAssessment, error)
+	UpdateAssessment(ctx context.Context, assessment *entity.RiskAssessment) error
+}
+
+// ReviewTransactions changes locked transactions. It is implemented by TransactionService.
+type ReviewTransactions interface {
+	change(
+		ctx context.Context, id uuid.UUID, eventType, action string, fn func(*entity.Transaction) (string, error),
+	) (*dto.Transaction, error)
+}
+
+// ReviewService resolves the transactions held for review by the risk screening.
+type ReviewService struct {
+	transactor   Transactor
+	assessments  RiskAssessments
+	transactions ReviewTransactions
+}
+
+func NewReviewService(transactor Transactor, assessments RiskAssessments, transactions ReviewTransactions) *ReviewService {
+	return &ReviewService{transactor: transactor, assessments: assessments, transactions: transactions}
+}
+
+// Approve releases a transaction in review back to created.
+func (s *ReviewService) Approve(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	return s.resolve(ctx, id, RiskApproved, createdStatus, TransactionApprovedEvent, HistoryApproved)
+}
+
+// Decline moves a transaction in review to declined.
+func (s *ReviewService) Decline(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	return s.resolve(ctx, id, RiskDeclined, declinedStatus, TransactionDeclinedEvent, HistoryDeclined)
+}
+
+func (s *ReviewService) resolve(
+	ctx context.Context, id uuid.UUID, resolution, status, eventType, action string) (*dto.Transaction, error) {
+	inReview := func(transaction *entity.Transaction) (string, error) {
+		if transaction.Status.Name != reviewStatus {
+			return "", fmt.Errorf("%w: transaction is not in review", ErrInvalidTransition)
+		}
+
+		return status, nil
+	}
+
+	var transaction *dto.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.transactions.change(ctx, id, eventType, action, inReview)
+		if err != nil {
+			return err
+		}
+
+		assessment, err := s.assessments.FindAssessment(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRiskAssessmentNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		resolvedAt := time.Now()
+		assessment.Resolution = resolution
+		assessment.ResolvedBy = requestctx.Actor(ctx)
+		assessment.ResolvedAt = &resolvedAt
+
+		return s.assessments.UpdateAssessment(ctx, assessment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
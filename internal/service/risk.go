@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	RiskApprove = "approve"
+	RiskReview  = "review"
+	RiskDecline = "decline"
+
+	velocityRule  = "velocity"
+	amountRule    = "amount"
+	blocklistRule = "blocklist"
+	currencyRule  = "currency"
+
+	metadataFieldPrefix = "metadata."
+	maxRiskScore        = 100
+	maxRiskValues       = 1000
+)
+
+type RiskRepository interface {
+	CreateRule(ctx context.Context, rule *entity.RiskRule) error
+	FindRuleByID(ctx context.Context, id uuid.UUID) (*entity.RiskRule, error)
+	FindRules(ctx context.Context, includeDisabled bool) ([]entity.RiskRule, error)
+	UpdateRule(ctx context.Context, rule *entity.RiskRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	CreateAssessment(ctx context.Context, assessment *entity.RiskAssessment) error
+	FindAssessment(ctx context.Context, transactionID uuid.UUID) (*entity.RiskAssessment, error)
+}
+
+type VelocityCounter interface {
+	CountByCustomerSince(ctx context.Context, customerID uuid.UUID, since time.Time) (int64, error)
+}
+
+type RiskMapper interface {
+	RuleToDTO(rule *entity.RiskRule) *dto.RiskRule
+	RuleFromDTO(rule *dto.RiskRule) *entity.RiskRule
+	AssessmentToDTO(assessment *entity.RiskAssessment) *dto.RiskAssessment
+}
+
+// RiskService manages the risk rules and screens new transactions with them. Transactions scoring
+// reviewScore or more are held for review, and those scoring declineScore or more are declined.
+// Amount rules compare settlement amounts, so they need a settlement currency.
+type RiskService struct {
+	repository         RiskRepository
+	counter            VelocityCounter
+	mapper             RiskMapper
+	reviewScore        int
+	declineScore       int
+	settlementCurrency string
+}
+
+func NewRiskService(
+	repository RiskRepository, counter VelocityCounter, mapper RiskMapper, reviewScore, declineScore int,
+	settlementCurrency string) *RiskService {
+	return &RiskService{
+		repository:         repository,
+		counter:            counter,
+		mapper:             mapper,
+		reviewScore:        reviewScore,
+		declineScore:       declineScore,
+		settlementCurrency: currency.Normalize(settlementCurrency),
+	}
+}
+
+func (s *RiskService) CreateRule(ctx context.Context, input *dto.RiskRule) (*dto.RiskRule, error) {
+	rule := s.mapper.RuleFromDTO(input)
+	rule.ID = uuid.Nil
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.RuleToDTO(rule), nil
+}
+
+func (s *RiskService) GetRuleByID(ctx context.Context, id uuid.UUID) (*dto.RiskRule, error) {
+	rule, err := s.findRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.RuleToDTO(rule), nil
+}
+
+func (s *RiskService) GetRules(ctx context.Context) ([]dto.RiskRule, error) {
+	rules, err := s.repository.FindRules(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.RiskRule, len(rules))
+	for i := range rules {
+		dtos[i] = *s.mapper.RuleToDTO(&rules[i])
+	}
+
+	return dtos, nil
+}
+
+func (s *RiskService) UpdateRule(ctx context.Context, id uuid.UUID, input *dto.RiskRule) (*dto.RiskRule, error) {
+	existing, err := s.findRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := s.mapper.RuleFromDTO(input)
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err = s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err = s.repository.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.RuleToDTO(rule), nil
+}
+
+func (s *RiskService) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	err := s.repository.DeleteRule(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrRiskRuleNotFound
+	}
+
+	return err
+}
+
+// GetAssessment returns the screening result of a transaction.
+func (s *RiskService) GetAssessment(ctx context.Context, transactionID uuid.UUID) (*dto.RiskAssessment, error) {
+	assessment, err := s.repository.FindAssessment(ctx, transactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRiskAssessmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.AssessmentToDTO(assessment), nil
+}
+
+// Screen scores a new transaction with the enabled rules and decides whether to approve, review
+// or decline it. The assessment is saved by Record once the transaction has an ID.
+func (s *RiskService) Screen(ctx context.Context, transaction *entity.Transaction) (*entity.RiskAssessment, error) {
+	rules, err := s.repository.FindRules(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment := &entity.RiskAssessment{Decision: RiskApprove, Hits: entity.RiskHits{}, CreatedAt: time.Now()}
+	for i := range rules {
+		hit, err := s.matches(ctx, &rules[i], transaction)
+		if err != nil {
+			return nil, err
+		}
+		if hit {
+			assessment.Score += rules[i].Score
+			assessment.Hits = append(assessment.Hits, entity.RiskHit{RuleID: rules[i].ID, Name: rules[i].Name, Score: rules[i].Score})
+		}
+	}
+
+	assessment.Score = min(assessment.Score, maxRiskScore)
+	switch {
+	case assessment.Score >= s.declineScore:
+		assessment.Decision = RiskDecline
+	case assessment.Score >= s.reviewScore:
+		assessment.Decision = RiskReview
+	}
+
+	return assessment, nil
+}
+
+// Record saves the assessment of a transaction created after Screen.
+func (s *RiskService) Record(ctx context.Context, assessment *entity.RiskAssessment) error {
+	return s.repository.CreateAssessment(ctx, assessment)
+}
+
+func (s *RiskService) matches(ctx context.Context, rule *entity.RiskRule, transaction *entity.Transaction) (bool, error) {
+	switch rule.Type {
+	case velocityRule:
+		if transaction.CustomerID == nil {
+			return false, nil
+		}
+		since := time.Now().Add(-time.Duration(rule.WindowSeconds) * time.Second)
+		count, err := s.counter.CountByCustomerSince(ctx, *transaction.CustomerID, since)
+		return count >= rule.MaxCount, err
+	case amountRule:
+		// Amount rules created with a settlement currency do not match after it was unset.
+		return s.settlementCurrency != "" && settlementShare(transaction, transaction.Amount) >= rule.Threshold, nil
+	case blocklistRule:
+		value := riskFieldValue(transaction, rule.Field)
+		return value != "" && slices.Contains(rule.Values, value), nil
+	case currencyRule:
+		return !slices.Contains(rule.Values, transaction.Currency), nil
+	default:
+		return false, nil
+	}
+}
+
+// riskFieldValue is the value of a blocklist field of transaction, empty when it is not set.
+func riskFieldValue(transaction *entity.Transaction, field string) string {
+	switch {
+	case field == "customer_id" && transaction.CustomerID != nil:
+		return transaction.CustomerID.String()
+	case field == "merchant_id" && transaction.MerchantID != nil:
+		return transaction.MerchantID.String()
+	case strings.HasPrefix(field, metadataFieldPrefix):
+		return transaction.Metadata[strings.TrimPrefix(field, metadataFieldPrefix)]
+	default:
+		return ""
+	}
+}
+
+// validateRule validates rule and rejects amount rules without a settlement currency to compare
+// amounts in.
+func (s *RiskService) validateRule(rule *entity.RiskRule) error {
+	if err := validateRiskRule(rule); err != nil {
+		return err
+	}
+	if rule.Type == amountRule && s.settlementCurrency == "" {
+		return fmt.Errorf("%w: amount rules need a settlement currency", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// validateRiskRule normalizes rule and rejects rules that cannot be evaluated, clearing the fields
+// its type does not use.
+func validateRiskRule(rule *entity.RiskRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if rule.Score < 1 || rule.Score > maxRiskScore {
+		return fmt.Errorf("%w: score must be between 1 and %d", ErrInvalidInput, maxRiskScore)
+	}
+	if len(rule.Values) > maxRiskValues {
+		return fmt.Errorf("%w: at most %d values are allowed", ErrInvalidInput, maxRiskValues)
+	}
+
+	switch rule.Type {
+	case velocityRule:
+		rule.Threshold, rule.Field, rule.Values = 0, "", nil
+		if rule.MaxCount < 1 || rule.WindowSeconds <= 0 {
+			return fmt.Errorf("%w: velocity rules need a positive window_seconds and max_count", ErrInvalidInput)
+		}
+	case amountRule:
+		rule.MaxCount, rule.WindowSeconds, rule.Field, rule.Values = 0, 0, "", nil
+		if rule.Threshold <= 0 {
+			return fmt.Errorf("%w: amount rules need a positive threshold", ErrInvalidInput)
+		}
+	case blocklistRule:
+		rule.Threshold, rule.MaxCount, rule.WindowSeconds = 0, 0, 0
+		return validateBlocklist(rule)
+	case currencyRule:
+		rule.Threshold, rule.MaxCount, rule.WindowSeconds, rule.Field = 0, 0, 0, ""
+		return validateUsualCurrencies(rule)
+	default:
+		return fmt.Errorf("%w: type must be %s, %s, %s or %s", ErrInvalidInput, velocityRule, amountRule, blocklistRule, currencyRule)
+	}
+
+	return nil
+}
+
+func validateBlocklist(rule *entity.RiskRule) error {
+	if rule.Field != "customer_id" && rule.Field != "merchant_id" &&
+		(!strings.HasPrefix(rule.Field, metadataFieldPrefix) || rule.Field == metadataFieldPrefix) {
+		return fmt.Errorf("%w: field must be customer_id, merchant_id or metadata.<key>", ErrInvalidInput)
+	}
+	if len(rule.Values) == 0 {
+		return fmt.Errorf("%w: blocklist rules need values", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+func validateUsualCurrencies(rule *entity.RiskRule) error {
+	if len(rule.Values) == 0 {
+		return fmt.Errorf("%w: currency rules need the usual currencies as values", ErrInvalidInput)
+	}
+	for i, code := range rule.Values {
+		rule.Values[i] = currency.Normalize(code)
+		if err := currency.Validate(rule.Values[i]); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *RiskService) findRuleByID(ctx context.Context, id uuid.UUID) (*entity.RiskRule, error) {
+	rule, err := s.repository.FindRuleByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRiskRuleNotFound
+	}
+
+	return rule, err
+}
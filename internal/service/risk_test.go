@@ -0,0 +1,34 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+func TestValidateRiskRuleVelocity(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxCount      int64
+		windowSeconds int64
+		want          error
+	}{
+		{"valid", 5, 3600, nil},
+		{"one transaction", 1, 3600, nil},
+		{"zero max_count", 0, 3600, ErrInvalidInput},
+		{"negative max_count", -1, 3600, ErrInvalidInput},
+		{"zero window", 5, 0, ErrInvalidInput},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateRiskRule(&entity.RiskRule{
+				Name: "velocity", Type: velocityRule, Score: 10, MaxCount: test.maxCount, WindowSeconds: test.windowSeconds,
+			})
+			if !errors.Is(err, test.want) || (test.want == nil && err != nil) {
+				t.Fatalf("validateRiskRule() error = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
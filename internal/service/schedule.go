@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/cron"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	ScheduleActive    = "active"
+	SchedulePaused    = "paused"
+	ScheduleCancelled = "cancelled"
+	ScheduleCompleted = "completed"
+
+	CatchUpAll    = "all"
+	CatchUpLatest = "latest"
+
+	// ScheduleIDKey and ScheduledAtKey are added to the metadata of the transactions a schedule
+	// creates.
+	ScheduleIDKey  = "schedule_id"
+	ScheduledAtKey = "scheduled_at"
+
+	scheduleLockKey     = 5_000_006
+	scheduleBatchSize   = 100
+	minScheduleInterval = time.Minute
+	// maxCatchUpRuns bounds the runs a schedule catches up per scheduler run, so a long downtime is
+	// caught up over several runs without starving the other schedules.
+	maxCatchUpRuns = 100
+	// scheduleStartGrace is how far in the past start_at may be, for clocks and requests in flight.
+	scheduleStartGrace = time.Minute
+)
+
+// scheduleTransitions is the schedule status graph. Completed is reached by running past the end
+// of the schedule.
+var scheduleTransitions = map[string][]string{
+	ScheduleActive:    {SchedulePaused, ScheduleCancelled},
+	SchedulePaused:    {ScheduleActive, ScheduleCancelled},
+	ScheduleCancelled: nil,
+	ScheduleCompleted: nil,
+}
+
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *entity.Schedule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Schedule, error)
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Schedule, error)
+	FindAll(ctx context.Context, query repository.ScheduleQuery) ([]entity.Schedule, error)
+	Update(ctx context.Context, schedule *entity.Schedule) error
+	FindDue(ctx context.Context, status string, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+type ScheduleMapper interface {
+	ToDTO(schedule *entity.Schedule) *dto.Schedule
+	FromDTO(schedule *dto.Schedule) *entity.Schedule
+}
+
+type ScheduledTransactions interface {
+	Create(ctx context.Context, input *dto.Transaction) (*dto.Transaction, error)
+}
+
+type SessionLocker interface {
+	WithSessionLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+// ScheduleService manages recurring transactions and creates them when they come due.
+type ScheduleService struct {
+	transactor   Transactor
+	locker       SessionLocker
+	repository   ScheduleRepository
+	transactions ScheduledTransactions
+	merchants    MerchantRepository
+	customers    CustomerRepository
+	mapper       ScheduleMapper
+}
+
+func NewScheduleService(
+	transactor Transactor,
+	locker SessionLocker,
+	repository ScheduleRepository,
+	transactions ScheduledTransactions,
+	merchants MerchantRepository,
+	customers CustomerRepository,
+	mapper ScheduleMapper) *ScheduleService {
+	return &ScheduleService{
+		transactor:   transactor,
+		locker:       locker,
+		repository:   repository,
+		transactions: transactions,
+		merchants:    merchants,
+		customers:    customers,
+		mapper:       mapper,
+	}
+}
+
+func (s *ScheduleService) Create(ctx context.Context, input *dto.Schedule) (*dto.Schedule, error) {
+	schedule := s.mapper.FromDTO(input)
+	if err := s.validate(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	firstRun, err := firstRun(schedule)
+	if err != nil {
+		return nil, err
+	}
+	if firstRun.IsZero() || (schedule.EndAt != nil && firstRun.After(*schedule.EndAt)) {
+		return nil, fmt.Errorf("%w: schedule never runs between start_at and end_at", ErrInvalidInput)
+	}
+
+	schedule.Status = ScheduleActive
+	schedule.NextRunAt = &firstRun
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+	if err = s.repository.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(schedule), nil
+}
+
+func (s *ScheduleService) GetByID(ctx context.Context, id uuid.UUID) (*dto.Schedule, error) {
+	schedule, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(schedule), nil
+}
+
+// GetAll returns the newest schedules matching filter.
+func (s *ScheduleService) GetAll(ctx context.Context, filter dto.ScheduleFilter) ([]dto.Schedule, error) {
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := scheduleTransitions[filter.Status]; filter.Status != "" && !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStatus, filter.Status)
+	}
+
+	schedules, err := s.repository.FindAll(ctx, repository.ScheduleQuery{
+		MerchantID: filter.MerchantID,
+		CustomerID: filter.CustomerID,
+		Status:     filter.Status,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Schedule, len(schedules))
+	for i := range schedules {
+		dtos[i] = *s.mapper.ToDTO(&schedules[i])
+	}
+
+	return dtos, nil
+}
+
+// Pause stops an active schedule from running until it is resumed.
+func (s *ScheduleService) Pause(ctx context.Context, id uuid.UUID) (*dto.Schedule, error) {
+	return s.change(ctx, id, func(schedule *entity.Schedule) error {
+		return transitionSchedule(schedule, SchedulePaused)
+	})
+}
+
+// Resume reactivates a paused schedule. The runs missed while it was paused are skipped, not caught
+// up.
+func (s *ScheduleService) Resume(ctx context.Context, id uuid.UUID) (*dto.Schedule, error) {
+	return s.change(ctx, id, func(schedule *entity.Schedule) error {
+		if err := transitionSchedule(schedule, ScheduleActive); err != nil {
+			return err
+		}
+		if schedule.NextRunAt == nil || schedule.NextRunAt.After(time.Now()) {
+			return nil
+		}
+
+		missed, err := lastDueRun(schedule, *schedule.NextRunAt, time.Now())
+		if err != nil {
+			return err
+		}
+		return advance(schedule, missed)
+	})
+}
+
+// Cancel stops a schedule for good.
+func (s *ScheduleService) Cancel(ctx context.Context, id uuid.UUID) (*dto.Schedule, error) {
+	return s.change(ctx, id, func(schedule *entity.Schedule) error {
+		if err := transitionSchedule(schedule, ScheduleCancelled); err != nil {
+			return err
+		}
+		schedule.NextRunAt = nil
+
+		return nil
+	})
+}
+
+func (s *ScheduleService) change(
+	ctx context.Context, id uuid.UUID, fn func(schedule *entity.Schedule) error) (*dto.Schedule, error) {
+	var schedule *entity.Schedule
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		schedule, err = s.repository.FindByIDForUpdate(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrScheduleNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = fn(schedule); err != nil {
+			return err
+		}
+		schedule.UpdatedAt = time.Now()
+
+		return s.repository.Update(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(schedule), nil
+}
+
+// Run creates the transactions of the active schedules that came due, including up to
+// maxCatchUpRuns runs per schedule missed while no replica was running. Only the replica holding the
+// schedule lock runs them, each run in a transaction of its own so that a failing run does not undo
+// the others.
+func (s *ScheduleService) Run(ctx context.Context) error {
+	_, err := s.locker.WithSessionLock(ctx, scheduleLockKey, func(ctx context.Context) error {
+		caughtUp := map[uuid.UUID]bool{}
+		for {
+			ids, err := s.repository.FindDue(ctx, ScheduleActive, time.Now(), scheduleBatchSize)
+			if err != nil {
+				return err
+			}
+
+			fresh := 0
+			for _, id := range ids {
+				if caughtUp[id] {
+					continue
+				}
+				caughtUp[id] = true
+				fresh++
+				if err = s.catchUp(ctx, id); err != nil {
+					return err
+				}
+			}
+			if len(ids) < scheduleBatchSize || fresh == 0 {
+				return nil
+			}
+		}
+	})
+
+	return err
+}
+
+// catchUp runs the schedule id until its next run is in the future, at most maxCatchUpRuns times.
+func (s *ScheduleService) catchUp(ctx context.Context, id uuid.UUID) error {
+	for range maxCatchUpRuns {
+		ran, err := s.runNext(ctx, id)
+		if err != nil || !ran {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runNext creates the transaction of the next due run of the schedule id and reports whether there
+// was one. A run whose transaction is rejected, for example by a limit, is skipped and its error
+// kept in LastError; other errors leave it due for the next attempt.
+func (s *ScheduleService) runNext(ctx context.Context, id uuid.UUID) (bool, error) {
+	var runAt time.Time
+	due := false
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		schedule, err := s.repository.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if runAt, due, err = dueRun(schedule, time.Now()); err != nil || !due {
+			return err
+		}
+
+		transaction, err := s.transactions.Create(ctx, scheduledTransaction(schedule, runAt))
+		if err != nil {
+			return err
+		}
+
+		schedule.RunCount++
+		schedule.LastTransactionID = &transaction.ID
+		schedule.LastError = ""
+		return s.finishRun(ctx, schedule, runAt)
+	})
+	if due && rejected(err) {
+		return true, s.skipRun(ctx, id, runAt, err)
+	}
+
+	return due, err
+}
+
+// skipRun moves the schedule id past runAt after its transaction was rejected with cause.
+func (s *ScheduleService) skipRun(ctx context.Context, id uuid.UUID, runAt time.Time, cause error) error {
+	return s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		schedule, err := s.repository.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if schedule.Status != ScheduleActive || schedule.NextRunAt == nil || schedule.NextRunAt.After(runAt) {
+			return nil
+		}
+
+		schedule.LastError = cause.Error()
+		return s.finishRun(ctx, schedule, runAt)
+	})
+}
+
+func (s *ScheduleService) finishRun(ctx context.Context, schedule *entity.Schedule, runAt time.Time) error {
+	schedule.LastRunAt = &runAt
+	if err := advance(schedule, runAt); err != nil {
+		return err
+	}
+	schedule.UpdatedAt = time.Now()
+
+	return s.repository.Update(ctx, schedule)
+}
+
+func (s *ScheduleService) validate(ctx context.Context, schedule *entity.Schedule) error {
+	schedule.Currency = currency.Normalize(schedule.Currency)
+	if err := validateMoney(schedule.Amount, schedule.Currency); err != nil {
+		return err
+	}
+	if err := validateMetadata(scheduledMetadata(schedule, time.Now())); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(schedule.Tags)
+	if err != nil {
+		return err
+	}
+	schedule.Tags = tags
+
+	if err = validateRecurrence(schedule); err != nil {
+		return err
+	}
+
+	return s.checkParties(ctx, schedule)
+}
+
+func (s *ScheduleService) checkParties(ctx context.Context, schedule *entity.Schedule) error {
+	if schedule.MerchantID != nil {
+		_, err := s.merchants.FindByID(ctx, *schedule.MerchantID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownMerchant, schedule.MerchantID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if schedule.CustomerID != nil {
+		_, err := s.customers.FindByID(ctx, *schedule.CustomerID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownCustomer, schedule.CustomerID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// validateRecurrence checks the cron expression or interval, catch up policy and bounds of a
+// schedule and normalizes the interval.
+func validateRecurrence(schedule *entity.Schedule) error {
+	if schedule.CatchUp == "" {
+		schedule.CatchUp = CatchUpAll
+	}
+	if schedule.CatchUp != CatchUpAll && schedule.CatchUp != CatchUpLatest {
+		return fmt.Errorf("%w: catch_up must be %s or %s", ErrInvalidInput, CatchUpAll, CatchUpLatest)
+	}
+	if schedule.StartAt.Before(time.Now().Add(-scheduleStartGrace)) {
+		return fmt.Errorf("%w: start_at must not be in the past", ErrInvalidInput)
+	}
+	if schedule.EndAt != nil && !schedule.EndAt.After(schedule.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidInput)
+	}
+
+	if (schedule.Cron == "") == (schedule.Interval == "") {
+		return fmt.Errorf("%w: exactly one of cron and interval is required", ErrInvalidInput)
+	}
+	if schedule.Cron != "" {
+		if _, err := cron.Parse(schedule.Cron); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		return nil
+	}
+
+	interval, err := time.ParseDuration(schedule.Interval)
+	if err != nil || interval < minScheduleInterval {
+		return fmt.Errorf("%w: interval must be a duration of at least %s", ErrInvalidInput, minScheduleInterval)
+	}
+	schedule.Interval = interval.String()
+
+	return nil
+}
+
+// firstRun is StartAt with an interval and the first match of the cron expression from StartAt
+// otherwise.
+func firstRun(schedule *entity.Schedule) (time.Time, error) {
+	if schedule.Cron == "" {
+		return schedule.StartAt, nil
+	}
+
+	return nextRun(schedule, schedule.StartAt.Add(-time.Nanosecond))
+}
+
+// nextRun returns the run of schedule following after, or the zero time when its cron expression
+// does not match anymore.
+func nextRun(schedule *entity.Schedule, after time.Time) (time.Time, error) {
+	if schedule.Cron == "" {
+		interval, err := time.ParseDuration(schedule.Interval)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return after.Add(interval), nil
+	}
+
+	expression, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return expression.Next(after), nil
+}
+
+// advance moves the next run of schedule past runAt, completing the schedule when there is none
+// before its end.
+func advance(schedule *entity.Schedule, runAt time.Time) error {
+	next, err := nextRun(schedule, runAt)
+	if err != nil {
+		return err
+	}
+
+	if next.IsZero() || (schedule.EndAt != nil && next.After(*schedule.EndAt)) {
+		schedule.Status = ScheduleCompleted
+		schedule.NextRunAt = nil
+		return nil
+	}
+	schedule.NextRunAt = &next
+
+	return nil
+}
+
+// dueRun returns the run of an active schedule to create at now: the next run with the all catch
+// up policy and the latest missed run with latest.
+func dueRun(schedule *entity.Schedule, now time.Time) (time.Time, bool, error) {
+	if schedule.Status != ScheduleActive || schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+		return time.Time{}, false, nil
+	}
+	if schedule.CatchUp == CatchUpAll {
+		return *schedule.NextRunAt, true, nil
+	}
+
+	runAt, err := lastDueRun(schedule, *schedule.NextRunAt, now)
+	return runAt, err == nil, err
+}
+
+// lastDueRun returns the last run of schedule from runAt on that is due at now.
+func lastDueRun(schedule *entity.Schedule, runAt, now time.Time) (time.Time, error) {
+	for {
+		next, err := nextRun(schedule, runAt)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.IsZero() || next.After(now) || (schedule.EndAt != nil && next.After(*schedule.EndAt)) {
+			return runAt, nil
+		}
+		runAt = next
+	}
+}
+
+func scheduledTransaction(schedule *entity.Schedule, runAt time.Time) *dto.Transaction {
+	return &dto.Transaction{
+		MerchantID: schedule.MerchantID,
+		CustomerID: schedule.CustomerID,
+		Amount:     schedule.Amount,
+		Currency:   schedule.Currency,
+		Metadata:   scheduledMetadata(schedule, runAt),
+		Tags:       slices.Clone(schedule.Tags),
+	}
+}
+
+// scheduledMetadata is the metadata of schedule with the schedule ID and run time added.
+func scheduledMetadata(schedule *entity.Schedule, runAt time.Time) map[string]string {
+	metadata := maps.Clone(schedule.Metadata)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata[ScheduleIDKey] = schedule.ID.String()
+	metadata[ScheduledAtKey] = runAt.UTC().Format(time.RFC3339)
+
+	return metadata
+}
+
+// rejected reports whether a scheduled transaction failed for a reason retrying will not fix.
+func rejected(err error) bool {
+	for _, target := range []error{
+		ErrInvalidInput, ErrUnknownMerchant, ErrUnknownCustomer, ErrChargesExceedAmount, ErrRateUnavailable,
+		ErrCountLimitExceeded, ErrAmountLimitExceeded,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func transitionSchedule(schedule *entity.Schedule, status string) error {
+	for _, next := range scheduleTransitions[schedule.Status] {
+		if next == status {
+			schedule.Status = status
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: schedule %q to %q", ErrInvalidTransition, schedule.Status, status)
+}
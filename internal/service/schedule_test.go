@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTransactor) WithSessionLock(ctx context.Context, _ int64, fn func(ctx context.Context) error) (bool, error) {
+	return true, fn(ctx)
+}
+
+// fakeSchedules keeps schedules in memory, handing out copies like a database would.
+type fakeSchedules struct {
+	ScheduleRepository
+	schedules map[uuid.UUID]entity.Schedule
+}
+
+func (r *fakeSchedules) FindByIDForUpdate(_ context.Context, id uuid.UUID) (*entity.Schedule, error) {
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	return &schedule, nil
+}
+
+func (r *fakeSchedules) Update(_ context.Context, schedule *entity.Schedule) error {
+	r.schedules[schedule.ID] = *schedule
+	return nil
+}
+
+func (r *fakeSchedules) FindDue(_ context.Context, status string, before time.Time, limit int) ([]uuid.UUID, error) {
+	var due []entity.Schedule
+	for _, schedule := range r.schedules {
+		if schedule.Status == status && schedule.NextRunAt != nil && !schedule.NextRunAt.After(before) {
+			due = append(due, schedule)
+		}
+	}
+	slices.SortFunc(due, func(a, b entity.Schedule) int { return a.NextRunAt.Compare(*b.NextRunAt) })
+
+	var ids []uuid.UUID
+	for i := 0; i < len(due) && i < limit; i++ {
+		ids = append(ids, due[i].ID)
+	}
+
+	return ids, nil
+}
+
+// fakeScheduledTransactions counts the transactions created per schedule.
+type fakeScheduledTransactions struct {
+	created map[string]int
+}
+
+func (f *fakeScheduledTransactions) Create(_ context.Context, input *dto.Transaction) (*dto.Transaction, error) {
+	f.created[input.Metadata[ScheduleIDKey]]++
+	return &dto.Transaction{ID: uuid.New()}, nil
+}
+
+func newTestScheduleService(schedules ...entity.Schedule) (*ScheduleService, *fakeSchedules, *fakeScheduledTransactions) {
+	repository := &fakeSchedules{schedules: map[uuid.UUID]entity.Schedule{}}
+	for _, schedule := range schedules {
+		repository.schedules[schedule.ID] = schedule
+	}
+	transactions := &fakeScheduledTransactions{created: map[string]int{}}
+
+	return NewScheduleService(fakeTransactor{}, fakeTransactor{}, repository, transactions, nil, nil, nil),
+		repository, transactions
+}
+
+// overdueSchedule runs every minute and missed its last runs, the latest a second ago so that the
+// next one is not due while the test runs.
+func overdueSchedule(catchUp string, missed int) entity.Schedule {
+	nextRunAt := time.Now().Add(-time.Second - time.Duration(missed-1)*time.Minute)
+	return entity.Schedule{
+		ID:        uuid.New(),
+		Amount:    100,
+		Currency:  "USD",
+		Interval:  time.Minute.String(),
+		CatchUp:   catchUp,
+		StartAt:   nextRunAt,
+		Status:    ScheduleActive,
+		NextRunAt: &nextRunAt,
+	}
+}
+
+func TestRunCatchUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		catchUp string
+		missed  int
+		want    []int
+	}{
+		{"all within the cap", CatchUpAll, 3, []int{3, 0}},
+		{"all at the cap", CatchUpAll, maxCatchUpRuns, []int{maxCatchUpRuns, 0}},
+		{"all over the cap", CatchUpAll, maxCatchUpRuns + 50, []int{maxCatchUpRuns, 50, 0}},
+		{"latest", CatchUpLatest, maxCatchUpRuns + 50, []int{1, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule := overdueSchedule(test.catchUp, test.missed)
+			service, schedules, transactions := newTestScheduleService(schedule)
+
+			for run, want := range test.want {
+				transactions.created = map[string]int{}
+				if err := service.Run(context.Background()); err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				if got := transactions.created[schedule.ID.String()]; got != want {
+					t.Fatalf("run %d created %d transactions, want %d", run, got, want)
+				}
+			}
+			if next := schedules.schedules[schedule.ID].NextRunAt; !next.After(time.Now()) {
+				t.Fatalf("next run %s is not in the future", next)
+			}
+		})
+	}
+}
+
+func TestRunCatchUpDoesNotStarveOtherSchedules(t *testing.T) {
+	behind := overdueSchedule(CatchUpAll, 10*maxCatchUpRuns)
+	due := overdueSchedule(CatchUpAll, 1)
+	service, _, transactions := newTestScheduleService(behind, due)
+
+	if err := service.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := transactions.created[behind.ID.String()]; got != maxCatchUpRuns {
+		t.Fatalf("schedule behind created %d transactions, want %d", got, maxCatchUpRuns)
+	}
+	if got := transactions.created[due.ID.String()]; got != 1 {
+		t.Fatalf("due schedule created %d transactions, want 1", got)
+	}
+}
+
+func TestValidateRecurrenceStartAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		startAt time.Time
+		want    error
+	}{
+		{"now", time.Now(), nil},
+		{"future", time.Now().Add(time.Hour), nil},
+		{"within the grace", time.Now().Add(-scheduleStartGrace / 2), nil},
+		{"past", time.Now().Add(-time.Hour), ErrInvalidInput},
+		{"far past", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), ErrInvalidInput},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateRecurrence(&entity.Schedule{Interval: "1h", StartAt: test.startAt})
+			if !errors.Is(err, test.want) || (test.want == nil && err != nil) {
+				t.Fatalf("validateRecurrence() error = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
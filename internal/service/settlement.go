@@ -0,0 +1,419 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
+)
+
+const (
+	SettlementOpen   = "open"
+	SettlementClosed = "closed"
+
+	SettlementBatchClosedEvent = "settlement_batch.closed"
+
+	settlementLockKey = 5_000_005
+
+	batchDateLayout = "2006-01-02"
+)
+
+// settledStatuses are the transaction statuses that are paid out in settlement batches.
+var settledStatuses = []string{completedStatus, partiallyRefundedStatus, refundedStatus}
+
+type SettlementRepository interface {
+	FindOrCreate(ctx context.Context, batch *entity.SettlementBatch) (*entity.SettlementBatch, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error)
+	FindAll(ctx context.Context, query repository.SettlementBatchQuery) ([]entity.SettlementBatch, error)
+	FindOpenBefore(ctx context.Context, status string, day time.Time) ([]entity.SettlementBatch, error)
+	Update(ctx context.Context, batch *entity.SettlementBatch) error
+	FindUnbatchedGroups(ctx context.Context, statuses []string) ([]repository.SettlementGroup, error)
+	Assign(ctx context.Context, batch *entity.SettlementBatch, statuses []string) (int64, error)
+	SumTransactions(ctx context.Context, batchID uuid.UUID) (*repository.SettlementTotals, error)
+	FindTransactions(ctx context.Context, batchID uuid.UUID) ([]entity.Transaction, error)
+	CreateEntries(ctx context.Context, entries []entity.SettlementEntry) error
+	FindEntries(ctx context.Context, batchID uuid.UUID) ([]entity.SettlementEntry, error)
+	SumEntries(ctx context.Context, batchID uuid.UUID) (*repository.SettlementTotals, error)
+	FindAdjustments(ctx context.Context) ([]repository.SettlementAdjustment, error)
+}
+
+type SettlementMapper interface {
+	ToDTO(batch *entity.SettlementBatch) *dto.SettlementBatch
+}
+
+// SettlementService groups the collected transactions of each merchant into daily payout batches.
+type SettlementService struct {
+	locker            Locker
+	repository        SettlementRepository
+	outbox            Outbox
+	mapper            SettlementMapper
+	transactionMapper TransactionMapper
+}
+
+func NewSettlementService(
+	locker Locker,
+	repository SettlementRepository,
+	outbox Outbox,
+	mapper SettlementMapper,
+	transactionMapper TransactionMapper) *SettlementService {
+	return &SettlementService{
+		locker:            locker,
+		repository:        repository,
+		outbox:            outbox,
+		mapper:            mapper,
+		transactionMapper: transactionMapper,
+	}
+}
+
+// Settle adds the collected transactions of merchants that belong to no batch yet to the open
+// batch of the current UTC day, books the refunds and chargebacks made after the batch of a
+// transaction closed there as adjustments and closes the batches of earlier days. Only one replica
+// settles at a time.
+func (s *SettlementService) Settle(ctx context.Context) error {
+	_, err := s.locker.WithAdvisoryLock(ctx, settlementLockKey, func(ctx context.Context) error {
+		today := periodStart(dailyPeriod, time.Now())
+		if err := s.assign(ctx, today); err != nil {
+			return err
+		}
+
+		return s.closeBefore(ctx, today)
+	})
+
+	return err
+}
+
+func (s *SettlementService) assign(ctx context.Context, today time.Time) error {
+	groups, err := s.repository.FindUnbatchedGroups(ctx, settledStatuses)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		batch, err := s.openBatch(ctx, group.MerchantID, group.Currency, today)
+		if err != nil {
+			return err
+		}
+
+		if _, err = s.repository.Assign(ctx, batch, settledStatuses); err != nil {
+			return err
+		}
+	}
+
+	return s.adjust(ctx, today)
+}
+
+// adjust books what transactions were refunded and charged back after their batch closed in the
+// open batch of their merchant and currency.
+func (s *SettlementService) adjust(ctx context.Context, today time.Time) error {
+	adjustments, err := s.repository.FindAdjustments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, adjustment := range adjustments {
+		batch, err := s.openBatch(ctx, adjustment.MerchantID, adjustment.Currency, today)
+		if err != nil {
+			return err
+		}
+
+		err = s.repository.CreateEntries(ctx, []entity.SettlementEntry{{
+			BatchID:              batch.ID,
+			TransactionID:        adjustment.TransactionID,
+			Adjustment:           true,
+			Status:               adjustment.Status,
+			TransactionCreatedAt: adjustment.TransactionCreatedAt,
+			Refunds:              adjustment.Refunds,
+			Chargebacks:          adjustment.Chargebacks,
+			CreatedAt:            time.Now(),
+		}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SettlementService) openBatch(
+	ctx context.Context, merchantID uuid.UUID, currency string, today time.Time) (*entity.SettlementBatch, error) {
+	return s.repository.FindOrCreate(ctx, &entity.SettlementBatch{
+		MerchantID: merchantID,
+		Currency:   currency,
+		BatchDate:  today,
+		Status:     SettlementOpen,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+}
+
+// closeBefore records the entries of the open batches of days before today, fixes their totals and
+// announces them.
+func (s *SettlementService) closeBefore(ctx context.Context, today time.Time) error {
+	batches, err := s.repository.FindOpenBefore(ctx, SettlementOpen, today)
+	if err != nil {
+		return err
+	}
+
+	for i := range batches {
+		batch := &batches[i]
+		transactions, err := s.repository.FindTransactions(ctx, batch.ID)
+		if err != nil {
+			return err
+		}
+		entries := make([]entity.SettlementEntry, len(transactions))
+		for j := range transactions {
+			entries[j] = settlementEntry(batch.ID, &transactions[j])
+		}
+		if err = s.repository.CreateEntries(ctx, entries); err != nil {
+			return err
+		}
+
+		closedAt := time.Now()
+		batch.Status = SettlementClosed
+		if err = s.fillTotals(ctx, batch); err != nil {
+			return err
+		}
+		batch.ClosedAt = &closedAt
+		batch.UpdatedAt = closedAt
+		if err = s.repository.Update(ctx, batch); err != nil {
+			return err
+		}
+
+		if err = s.outbox.Enqueue(ctx, batch.ID, SettlementBatchClosedEvent, s.mapper.ToDTO(batch)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetAll returns the newest batches matching filter. Open batches carry their running totals.
+func (s *SettlementService) GetAll(ctx context.Context, filter dto.SettlementBatchFilter) ([]dto.SettlementBatch, error) {
+	query, err := buildSettlementQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := s.repository.FindAll(ctx, *query)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.SettlementBatch, len(batches))
+	for i := range batches {
+		if batches[i].Status == SettlementOpen {
+			if err = s.fillTotals(ctx, &batches[i]); err != nil {
+				return nil, err
+			}
+		}
+		dtos[i] = *s.mapper.ToDTO(&batches[i])
+	}
+
+	return dtos, nil
+}
+
+func (s *SettlementService) GetByID(ctx context.Context, id uuid.UUID) (*dto.SettlementBatch, error) {
+	batch, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Status == SettlementOpen {
+		if err = s.fillTotals(ctx, batch); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.mapper.ToDTO(batch), nil
+}
+
+// GetTransactions returns the transactions of a batch as they are now, oldest first. Export shows
+// what a closed batch paid out for them.
+func (s *SettlementService) GetTransactions(ctx context.Context, id uuid.UUID) ([]dto.Transaction, error) {
+	transactions, err := s.findTransactions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.Transaction, len(transactions))
+	for i := range transactions {
+		dtos[i] = *s.transactionMapper.ToDTO(&transactions[i])
+	}
+
+	return dtos, nil
+}
+
+// Export returns the entries of a batch as CSV, one row per transaction or adjustment with the
+// amounts that make up the batch totals. The rows of an open batch show its transactions as they
+// are now and those of a closed batch as they were settled.
+func (s *SettlementService) Export(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	batch, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entries(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	out := csv.NewWriter(&buffer)
+	if err = out.Write([]string{
+		"transaction_id", "type", "created_at", "status", "currency", "gross", "fees", "tax", "refunds", "chargebacks", "net",
+	}); err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		entryType := "transaction"
+		if entry.Adjustment {
+			entryType = "adjustment"
+		}
+		err = out.Write([]string{
+			entry.TransactionID.String(),
+			entryType,
+			entry.TransactionCreatedAt.UTC().Format(time.RFC3339),
+			entry.Status,
+			batch.Currency,
+			strconv.FormatInt(entry.Gross, 10),
+			strconv.FormatInt(entry.Fees, 10),
+			strconv.FormatInt(entry.Tax, 10),
+			strconv.FormatInt(entry.Refunds, 10),
+			strconv.FormatInt(entry.Chargebacks, 10),
+			strconv.FormatInt(entry.Gross-entry.Fees-entry.Tax-entry.Refunds-entry.Chargebacks, 10),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out.Flush()
+	return buffer.Bytes(), out.Error()
+}
+
+// entries returns the recorded entries of batch, preceded while it is open by entries made from its
+// transactions as they are now.
+func (s *SettlementService) entries(ctx context.Context, batch *entity.SettlementBatch) ([]entity.SettlementEntry, error) {
+	var entries []entity.SettlementEntry
+	if batch.Status == SettlementOpen {
+		transactions, err := s.repository.FindTransactions(ctx, batch.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range transactions {
+			entries = append(entries, settlementEntry(batch.ID, &transactions[i]))
+		}
+	}
+
+	recorded, err := s.repository.FindEntries(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(entries, recorded...), nil
+}
+
+func (s *SettlementService) findTransactions(ctx context.Context, id uuid.UUID) ([]entity.Transaction, error) {
+	if _, err := s.findByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repository.FindTransactions(ctx, id)
+}
+
+// fillTotals sets the totals of batch from its entries and, while it is open, its transactions.
+func (s *SettlementService) fillTotals(ctx context.Context, batch *entity.SettlementBatch) error {
+	totals, err := s.repository.SumEntries(ctx, batch.ID)
+	if err != nil {
+		return err
+	}
+	if batch.Status == SettlementOpen {
+		live, err := s.repository.SumTransactions(ctx, batch.ID)
+		if err != nil {
+			return err
+		}
+		totals.Count += live.Count
+		totals.Gross += live.Gross
+		totals.Fees += live.Fees
+		totals.Tax += live.Tax
+		totals.Refunds += live.Refunds
+		totals.Chargebacks += live.Chargebacks
+	}
+
+	batch.Count = totals.Count
+	batch.Gross = totals.Gross
+	batch.Fees = totals.Fees
+	batch.Tax = totals.Tax
+	batch.Refunds = totals.Refunds
+	batch.Chargebacks = totals.Chargebacks
+	batch.Net = totals.Gross - totals.Fees - totals.Tax - totals.Refunds - totals.Chargebacks
+
+	return nil
+}
+
+// settlementEntry is what batch pays out for transaction as it is now.
+func settlementEntry(batchID uuid.UUID, transaction *entity.Transaction) entity.SettlementEntry {
+	return entity.SettlementEntry{
+		BatchID:              batchID,
+		TransactionID:        transaction.ID,
+		Status:               transaction.Status.Name,
+		TransactionCreatedAt: transaction.CreatedAt,
+		Gross:                grossAmount(transaction),
+		Fees:                 transaction.FeeAmount,
+		Tax:                  transaction.TaxAmount,
+		Refunds:              transaction.RefundedAmount,
+		Chargebacks:          transaction.ChargedBackAmount,
+		CreatedAt:            time.Now(),
+	}
+}
+
+func (s *SettlementService) findByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error) {
+	batch, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSettlementBatchNotFound
+	}
+
+	return batch, err
+}
+
+func buildSettlementQuery(filter dto.SettlementBatchFilter) (*repository.SettlementBatchQuery, error) {
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Status != "" && filter.Status != SettlementOpen && filter.Status != SettlementClosed {
+		return nil, fmt.Errorf("%w: status must be open or closed", ErrInvalidInput)
+	}
+
+	query := &repository.SettlementBatchQuery{MerchantID: filter.MerchantID, Status: filter.Status, Limit: limit}
+	if query.From, err = parseBatchDate("from", filter.From); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseBatchDate("to", filter.To); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+func parseBatchDate(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	day, err := time.Parse(batchDateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a date formatted as YYYY-MM-DD", ErrInvalidInput, name)
+	}
+
+	return &day, nil
+}
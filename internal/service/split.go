@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+const (
+	SplitInstallments = "installments"
+	SplitMerchants    = "merchants"
+
+	TransactionSplitEvent = "transaction.split"
+
+	maxSplitParts = 60
+)
+
+// failedStatuses are the statuses in which a child of a split transaction will never be paid.
+var failedStatuses = map[string]bool{
+	voidedStatus:   true,
+	declinedStatus: true,
+	deletedStatus:  true,
+	expiredStatus:  true,
+}
+
+// Split divides a created transaction into installments or merchant shares. Each part becomes a
+// child transaction, priced and converted like a new one, and the parent moves to split. From
+// then on the parent follows its children: partially_paid once one is collected, then completed
+// once all are collected or failed, or voided when none was collected.
+func (s *TransactionService) Split(ctx context.Context, id uuid.UUID, input *dto.Split) ([]dto.Transaction, error) {
+	var children []entity.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		parent, err := s.findByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if parent.Status.Name != createdStatus || parent.ParentID != nil {
+			return fmt.Errorf("%w: only a created transaction that is not a child can be split", ErrInvalidTransition)
+		}
+
+		parts, err := splitParts(parent, input)
+		if err != nil {
+			return err
+		}
+
+		before := s.mapper.ToDTO(parent)
+		parent.SplitType = input.Type
+		if err = s.transition(parent, splitStatus); err != nil {
+			return err
+		}
+		parent.UpdatedAt = time.Now()
+
+		if err = s.repository.Update(ctx, parent); err != nil {
+			return err
+		}
+		if err = s.recordChange(ctx, TransactionSplitEvent, HistorySplit, before, parent); err != nil {
+			return err
+		}
+
+		for i, part := range parts {
+			installment := 0
+			if input.Type == SplitInstallments {
+				installment = i + 1
+			}
+
+			child, err := s.createChild(ctx, parent, installment, part)
+			if err != nil {
+				return err
+			}
+			children = append(children, *child)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toDTOs(children), nil
+}
+
+// GetChildren returns the children of a split transaction, deleted ones included, in installment
+// and creation order.
+func (s *TransactionService) GetChildren(ctx context.Context, id uuid.UUID) ([]dto.Transaction, error) {
+	if _, err := s.findByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	children, err := s.repository.FindChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toDTOs(children), nil
+}
+
+// createChild creates the part of parent described by part. The parent was screened and counted
+// against the limits already, so the child is not.
+func (s *TransactionService) createChild(
+	ctx context.Context, parent *entity.Transaction, installment int, part dto.SplitPart) (*entity.Transaction, error) {
+	child := &entity.Transaction{
+		ParentID:    &parent.ID,
+		MerchantID:  parent.MerchantID,
+		CustomerID:  parent.CustomerID,
+		Amount:      part.Amount,
+		Currency:    parent.Currency,
+		Metadata:    maps.Clone(parent.Metadata),
+		Tags:        slices.Clone(parent.Tags),
+		Installment: installment,
+		DueAt:       part.DueAt,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if part.MerchantID != nil {
+		child.MerchantID = part.MerchantID
+	}
+
+	if err := s.checkParties(ctx, child); err != nil {
+		return nil, err
+	}
+	if err := s.pricer.Price(ctx, child); err != nil {
+		return nil, err
+	}
+	if err := s.converter.Convert(ctx, child); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Create(ctx, child); err != nil {
+		return nil, err
+	}
+
+	return child, s.recordChange(ctx, TransactionCreatedEvent, HistoryCreated, nil, child)
+}
+
+// deriveParent moves a split transaction to the status its children add up to. A deleted parent is
+// left alone.
+func (s *TransactionService) deriveParent(ctx context.Context, parentID uuid.UUID) error {
+	parent, err := s.findByIDForUpdate(ctx, parentID)
+	if errors.Is(err, ErrTransactionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	children, err := s.repository.FindChildren(ctx, parentID)
+	if err != nil {
+		return err
+	}
+
+	status := deriveSplitStatus(children)
+	if status == parent.Status.Name {
+		return nil
+	}
+
+	before := s.mapper.ToDTO(parent)
+	err = s.transition(parent, status)
+	if errors.Is(err, ErrInvalidTransition) {
+		// Later changes of the children, such as deleting one, do not reopen a completed parent.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	parent.UpdatedAt = time.Now()
+
+	if err = s.repository.Update(ctx, parent); err != nil {
+		return err
+	}
+	return s.recordChange(ctx, TransactionUpdatedEvent, HistoryUpdated, before, parent)
+}
+
+func (s *TransactionService) toDTOs(transactions []entity.Transaction) []dto.Transaction {
+	dtos := make([]dto.Transaction, len(transactions))
+	for i := range transactions {
+		dtos[i] = *s.mapper.ToDTO(&transactions[i])
+	}
+
+	return dtos
+}
+
+// deriveSplitStatus is split while no child is collected, partially_paid while some are collected
+// and others still open, and completed or voided once every child is collected or failed.
+func deriveSplitStatus(children []entity.Transaction) string {
+	collected, failed := 0, 0
+	for i := range children {
+		switch {
+		case collectedStatuses[children[i].Status.Name]:
+			collected++
+		case failedStatuses[children[i].Status.Name]:
+			failed++
+		}
+	}
+
+	switch {
+	case collected+failed < len(children) && collected > 0:
+		return partiallyPaidStatus
+	case collected+failed < len(children):
+		return splitStatus
+	case collected > 0:
+		return completedStatus
+	default:
+		return voidedStatus
+	}
+}
+
+// splitParts validates a split of parent and returns its parts. Count equal installments are due
+// one month apart, the first one carrying the remainder of the division.
+func splitParts(parent *entity.Transaction, input *dto.Split) ([]dto.SplitPart, error) {
+	parts := input.Parts
+	switch input.Type {
+	case SplitInstallments:
+		if len(parts) == 0 {
+			if err := checkPartCount(input.Count); err != nil {
+				return nil, err
+			}
+			parts = equalInstallments(parent.Amount, input.Count, input.FirstDueAt)
+		}
+	case SplitMerchants:
+	default:
+		return nil, fmt.Errorf("%w: split type must be %s or %s", ErrInvalidInput, SplitInstallments, SplitMerchants)
+	}
+
+	if err := checkPartCount(len(parts)); err != nil {
+		return nil, err
+	}
+
+	var total int64
+	merchants := map[uuid.UUID]bool{}
+	for _, part := range parts {
+		if err := checkSplitPart(input.Type, part, merchants); err != nil {
+			return nil, err
+		}
+		total += part.Amount
+	}
+	if total != parent.Amount {
+		return nil, fmt.Errorf("%w: parts sum to %d instead of %d", ErrSplitMismatch, total, parent.Amount)
+	}
+
+	return parts, nil
+}
+
+func checkPartCount(count int) error {
+	if count < 2 || count > maxSplitParts {
+		return fmt.Errorf("%w: a split has 2 to %d parts", ErrInvalidInput, maxSplitParts)
+	}
+
+	return nil
+}
+
+func checkSplitPart(splitType string, part dto.SplitPart, merchants map[uuid.UUID]bool) error {
+	if part.Amount <= 0 {
+		return fmt.Errorf("%w: amount of every part must be positive", ErrInvalidInput)
+	}
+
+	if splitType == SplitInstallments {
+		if part.DueAt == nil || part.MerchantID != nil {
+			return fmt.Errorf("%w: every installment needs a due_at and no merchant_id", ErrInvalidInput)
+		}
+		return nil
+	}
+
+	if part.MerchantID == nil || part.DueAt != nil || merchants[*part.MerchantID] {
+		return fmt.Errorf("%w: every share needs its own merchant_id and no due_at", ErrInvalidInput)
+	}
+	merchants[*part.MerchantID] = true
+
+	return nil
+}
+
+func equalInstallments(amount int64, count int, firstDueAt *time.Time) []dto.SplitPart {
+	dueAt := time.Now()
+	if firstDueAt != nil {
+		dueAt = *firstDueAt
+	}
+
+	parts := make([]dto.SplitPart, count)
+	for i := range parts {
+		due := dueAt.AddDate(0, i, 0)
+		parts[i] = dto.SplitPart{Amount: amount / int64(count), DueAt: &due}
+	}
+	parts[0].Amount += amount % int64(count)
+
+	return parts
+}
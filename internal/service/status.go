@@ -1,14 +1,19 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/the-great-checkout/transactions-crud/internal/dto"
 	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
 )
 
 type StatusRepository interface {
 	Create(status *entity.Status) error
 	FindByID(id uuid.UUID) (*entity.Status, error)
+	FindByName(name string) (*entity.Status, error)
 	FindAll() ([]entity.Status, error)
 }
 
@@ -26,10 +31,23 @@ func NewStatusService(repository StatusRepository, mapper StatusMapper) *StatusS
 	return &StatusService{repository: repository, mapper: mapper}
 }
 
-func (s *StatusService) Create(name string) (*dto.Status, error) {
+func (s *StatusService) Create(name string, transitions []string) (*dto.Status, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: status name is required", ErrInvalidInput)
+	}
+
 	status := &entity.Status{
 		Name: name,
+		Next: make([]entity.Status, len(transitions)),
+	}
+	for i, transition := range transitions {
+		next, err := findStatusByName(s.repository, transition)
+		if err != nil {
+			return nil, err
+		}
+		status.Next[i] = *next
 	}
+
 	err := s.repository.Create(status)
 	if err != nil {
 		return nil, err
@@ -58,3 +76,24 @@ func (s *StatusService) GetAll() ([]dto.Status, error) {
 
 	return dtos, nil
 }
+
+// findStatusByName resolves a status name, reporting unknown names as ErrUnknownStatus.
+func findStatusByName(statuses StatusRepository, name string) (*entity.Status, error) {
+	status, err := statuses.FindByName(name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStatus, name)
+	}
+
+	return status, err
+}
+
+// canTransition reports whether a transaction in from may move to the status named to.
+func canTransition(from *entity.Status, to string) bool {
+	for i := range from.Next {
+		if from.Next[i].Name == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,25 +1,116 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/the-great-checkout/transactions-crud/internal/currency"
 	"github.com/the-great-checkout/transactions-crud/internal/dto"
 	"github.com/the-great-checkout/transactions-crud/internal/entity"
+	"github.com/the-great-checkout/transactions-crud/internal/repository"
 )
 
+const (
+	createdStatus           = "created"
+	pendingStatus           = "pending"
+	completedStatus         = "completed"
+	deletedStatus           = "deleted"
+	partiallyRefundedStatus = "partially_refunded"
+	refundedStatus          = "refunded"
+	authorizedStatus        = "authorized"
+	partiallyCapturedStatus = "partially_captured"
+	voidedStatus            = "voided"
+	reviewStatus            = "review"
+	declinedStatus          = "declined"
+	splitStatus             = "split"
+	partiallyPaidStatus     = "partially_paid"
+	expiredStatus           = "expired"
+
+	TransactionCreatedEvent  = "transaction.created"
+	TransactionUpdatedEvent  = "transaction.updated"
+	TransactionDeletedEvent  = "transaction.deleted"
+	TransactionRestoredEvent = "transaction.restored"
+)
+
+// managedStatuses are only entered through their own operations, never through Update.
+var managedStatuses = map[string]bool{
+	partiallyRefundedStatus: true,
+	refundedStatus:          true,
+	authorizedStatus:        true,
+	partiallyCapturedStatus: true,
+	voidedStatus:            true,
+	reviewStatus:            true,
+	declinedStatus:          true,
+	splitStatus:             true,
+	partiallyPaidStatus:     true,
+	expiredStatus:           true,
+}
+
 type TransactionRepository interface {
-	Create(transaction *entity.Transaction) error
-	FindByID(id uuid.UUID) (*entity.Transaction, error)
-	FindAll() ([]entity.Transaction, error)
-	Update(transaction *entity.Transaction) error
-	Delete(id uuid.UUID) (*entity.Transaction, error)
+	Create(ctx context.Context, transaction *entity.Transaction) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	FindAll(ctx context.Context, query repository.TransactionQuery) ([]entity.Transaction, error)
+	Update(ctx context.Context, transaction *entity.Transaction) error
+	Delete(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
+	Restore(ctx context.Context, id uuid.UUID, status *entity.Status) (*entity.Transaction, error)
+	FindChildren(ctx context.Context, parentID uuid.UUID) ([]entity.Transaction, error)
+}
+
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Outbox interface {
+	Enqueue(ctx context.Context, aggregateID uuid.UUID, eventType string, payload any) error
+}
+
+type History interface {
+	Record(ctx context.Context, action string, before, after *dto.Transaction) error
+	StatusBefore(ctx context.Context, transactionID uuid.UUID, action string) (string, error)
+}
+
+type Ledger interface {
+	Post(ctx context.Context, eventType string, before, after *dto.Transaction) error
+}
+
+type Pricer interface {
+	Price(ctx context.Context, transaction *entity.Transaction) error
+}
+
+type Converter interface {
+	Convert(ctx context.Context, transaction *entity.Transaction) error
+}
+
+type Screener interface {
+	Screen(ctx context.Context, transaction *entity.Transaction) (*entity.RiskAssessment, error)
+	Record(ctx context.Context, assessment *entity.RiskAssessment) error
+}
+
+type Limiter interface {
+	Consume(ctx context.Context, operation string, transaction *entity.Transaction, amount int64) error
 }
 
 type TransactionService struct {
-	repository TransactionRepository
-	mapper     TransactionMapper
+	transactor       Transactor
+	repository       TransactionRepository
+	statusRepository StatusRepository
+	merchants        MerchantRepository
+	customers        CustomerRepository
+	outbox           Outbox
+	history          History
+	ledger           Ledger
+	pricer           Pricer
+	converter        Converter
+	screener         Screener
+	limiter          Limiter
+	mapper           TransactionMapper
 }
 
 type TransactionMapper interface {
@@ -27,66 +118,675 @@ type TransactionMapper interface {
 	FromDTO(transaction *dto.Transaction) *entity.Transaction
 }
 
-func NewTransactionService(repository TransactionRepository, mapper TransactionMapper) *TransactionService {
-	return &TransactionService{repository: repository, mapper: mapper}
+func NewTransactionService(
+	transactor Transactor,
+	repository TransactionRepository,
+	statusRepository StatusRepository,
+	merchants MerchantRepository,
+	customers CustomerRepository,
+	outbox Outbox,
+	history History,
+	ledger Ledger,
+	pricer Pricer,
+	converter Converter,
+	screener Screener,
+	limiter Limiter,
+	mapper TransactionMapper) *TransactionService {
+	return &TransactionService{
+		transactor:       transactor,
+		repository:       repository,
+		statusRepository: statusRepository,
+		merchants:        merchants,
+		customers:        customers,
+		outbox:           outbox,
+		history:          history,
+		ledger:           ledger,
+		pricer:           pricer,
+		converter:        converter,
+		screener:         screener,
+		limiter:          limiter,
+		mapper:           mapper,
+	}
 }
 
-func (s *TransactionService) Create(value float64) (*dto.Transaction, error) {
-	transaction := &entity.Transaction{
-		Value:     value,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+func (s *TransactionService) Create(ctx context.Context, input *dto.Transaction) (*dto.Transaction, error) {
+	transaction, err := newTransaction(input)
+	if err != nil {
+		return nil, err
 	}
-	err := s.repository.Create(transaction)
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.checkParties(ctx, transaction); err != nil {
+			return err
+		}
+		if err := s.pricer.Price(ctx, transaction); err != nil {
+			return err
+		}
+		if err := s.converter.Convert(ctx, transaction); err != nil {
+			return err
+		}
+
+		assessment, err := s.screener.Screen(ctx, transaction)
+		if err != nil {
+			return err
+		}
+		if err = s.applyDecision(transaction, assessment.Decision); err != nil {
+			return err
+		}
+		if assessment.Decision != RiskDecline {
+			if err = s.limiter.Consume(ctx, LimitCreate, transaction, transaction.Amount); err != nil {
+				return err
+			}
+		}
+		if err = s.repository.Create(ctx, transaction); err != nil {
+			return err
+		}
+
+		assessment.TransactionID = transaction.ID
+		if err = s.screener.Record(ctx, assessment); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, TransactionCreatedEvent, HistoryCreated, nil, transaction)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return s.mapper.ToDTO(transaction), nil
 }
 
-func (s *TransactionService) GetByID(id uuid.UUID) (*dto.Transaction, error) {
-	transaction, err := s.repository.FindByID(id)
+// Quote returns the breakdown Create would store for input, without creating the transaction.
+func (s *TransactionService) Quote(ctx context.Context, input *dto.Transaction) (*dto.Breakdown, error) {
+	transaction, err := newTransaction(input)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkParties(ctx, transaction); err != nil {
+		return nil, err
+	}
+	if err = s.pricer.Price(ctx, transaction); err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(transaction).Breakdown, nil
+}
+
+// applyDecision holds a new transaction for review or declines it as the risk decision requires.
+func (s *TransactionService) applyDecision(transaction *entity.Transaction, decision string) error {
+	var name string
+	switch decision {
+	case RiskReview:
+		name = reviewStatus
+	case RiskDecline:
+		name = declinedStatus
+	default:
+		return nil
+	}
+
+	status, err := findStatusByName(s.statusRepository, name)
+	if err != nil {
+		return err
+	}
+	transaction.StatusID = status.ID
+	transaction.Status = *status
+
+	return nil
+}
+
+// newTransaction validates input and builds the transaction it describes.
+func newTransaction(input *dto.Transaction) (*entity.Transaction, error) {
+	lineItems, amount, err := priceLineItems(input.Amount, input.LineItems)
+	if err != nil {
+		return nil, err
+	}
+
+	currencyCode := currency.Normalize(input.Currency)
+	if err = validateMoney(amount, currencyCode); err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateMetadata(input.Metadata); err != nil {
+		return nil, err
+	}
+
+	return &entity.Transaction{
+		MerchantID: input.MerchantID,
+		CustomerID: input.CustomerID,
+		Amount:     amount,
+		Currency:   currencyCode,
+		Metadata:   input.Metadata,
+		Tags:       tags,
+		LineItems:  lineItems,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+func (s *TransactionService) GetByID(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	transaction, err := s.repository.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return s.mapper.ToDTO(transaction), nil
 }
 
-func (s *TransactionService) GetAll() ([]dto.Transaction, error) {
-	transactions, err := s.repository.FindAll()
+func (s *TransactionService) GetAll(ctx context.Context, filter dto.TransactionFilter) (*dto.TransactionPage, error) {
+	query, err := s.buildQuery(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	dtos := make([]dto.Transaction, len(transactions))
+	// Fetch one extra row to learn whether there is a next page.
+	limit := query.Limit
+	query.Limit++
+
+	transactions, err := s.repository.FindAll(ctx, *query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.TransactionPage{Items: make([]dto.Transaction, 0, limit)}
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		page.NextCursor = encodeCursor(cursor{
+			Sort:  filter.Sort,
+			Value: cursorValue(&last, query.SortField),
+			ID:    last.ID,
+		})
+	}
+
 	for i := range transactions {
-		dtos[i] = *s.mapper.ToDTO(&transactions[i])
+		page.Items = append(page.Items, *s.mapper.ToDTO(&transactions[i]))
 	}
 
-	return dtos, nil
+	return page, nil
 }
 
-func (s *TransactionService) Update(id uuid.UUID, status string, value float64) (*dto.Transaction, error) {
-	transaction, err := s.repository.FindByID(id)
+func (s *TransactionService) buildQuery(filter dto.TransactionFilter) (*repository.TransactionQuery, error) {
+	limit, err := pageSize(filter.Limit)
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		return nil, err
 	}
-	transaction.Status = entity.Status{Name: status}
-	transaction.Value = value
-	transaction.UpdatedAt = time.Now()
 
-	err = s.repository.Update(transaction)
+	sortField, descending, err := parseSort(filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &repository.TransactionQuery{
+		Tags:           filter.Tags,
+		MerchantID:     filter.MerchantID,
+		CustomerID:     filter.CustomerID,
+		Currency:       currency.Normalize(filter.Currency),
+		MinAmount:      filter.MinAmount,
+		MaxAmount:      filter.MaxAmount,
+		CreatedFrom:    filter.CreatedFrom,
+		CreatedTo:      filter.CreatedTo,
+		UpdatedFrom:    filter.UpdatedFrom,
+		UpdatedTo:      filter.UpdatedTo,
+		IncludeDeleted: filter.IncludeDeleted,
+		SortField:      sortField,
+		Descending:     descending,
+		Limit:          limit,
+	}
+
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, fmt.Errorf("%w: min_amount is greater than max_amount", ErrInvalidInput)
+	}
+
+	if len(filter.Metadata) > 0 {
+		query.Metadata = make(map[string]string, len(filter.Metadata))
+		for _, pair := range filter.Metadata {
+			key, value, found := strings.Cut(pair, ":")
+			if !found {
+				return nil, fmt.Errorf("%w: metadata filter %q is not key:value", ErrInvalidInput, pair)
+			}
+			query.Metadata[key] = value
+		}
+	}
+
+	if filter.Status != "" {
+		var status *entity.Status
+		status, err = findStatusByName(s.statusRepository, filter.Status)
+		if err != nil {
+			return nil, err
+		}
+		query.StatusID = &status.ID
+	}
+
+	if filter.Cursor != "" {
+		var c *cursor
+		c, err = decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != filter.Sort {
+			return nil, fmt.Errorf("%w: cursor was issued for a different sort", ErrInvalidInput)
+		}
+
+		query.AfterValue, err = parseCursorValue(sortField, c.Value)
+		if err != nil {
+			return nil, err
+		}
+		query.After = true
+		query.AfterID = c.ID
+	}
+
+	return query, nil
+}
+
+func cursorValue(transaction *entity.Transaction, field string) string {
+	switch field {
+	case "amount":
+		return strconv.FormatInt(transaction.Amount, 10)
+	case "updated_at":
+		return transaction.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return transaction.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
+// Update replaces the status, amount and, when present in input, the metadata and tags of a
+// transaction. A new amount is priced again with the current rules and converted at the current rate.
+func (s *TransactionService) Update(ctx context.Context, id uuid.UUID, input *dto.Transaction) (*dto.Transaction, error) {
+	return s.update(ctx, id, func(*dto.Transaction) (*dto.Transaction, error) {
+		return input, nil
+	})
+}
+
+// update applies to the locked transaction id the input fn derives from its current state, as
+// Update does.
+func (s *TransactionService) update(
+	ctx context.Context, id uuid.UUID, fn func(before *dto.Transaction) (*dto.Transaction, error),
+) (*dto.Transaction, error) {
+	var transaction *entity.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.findByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := s.mapper.ToDTO(transaction)
+
+		input, err := fn(before)
+		if err != nil {
+			return err
+		}
+		status, amount := input.Status, input.Amount
+		tags, err := normalizeTags(input.Tags)
+		if err != nil {
+			return err
+		}
+		if err = validateMetadata(input.Metadata); err != nil {
+			return err
+		}
+
+		if err = checkUpdate(transaction, input.Currency, amount, status); err != nil {
+			return err
+		}
+		if err = s.transition(transaction, status); err != nil {
+			return err
+		}
+		if amount != transaction.Amount {
+			transaction.Amount = amount
+			if err = s.pricer.Price(ctx, transaction); err != nil {
+				return err
+			}
+			if err = s.converter.Convert(ctx, transaction); err != nil {
+				return err
+			}
+		}
+		if input.Metadata != nil {
+			transaction.Metadata = input.Metadata
+		}
+		if input.Tags != nil {
+			transaction.Tags = tags
+		}
+		transaction.UpdatedAt = time.Now()
+
+		if err = s.repository.Update(ctx, transaction); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, TransactionUpdatedEvent, HistoryUpdated, before, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.mapper.ToDTO(transaction), nil
+}
+
+// checkUpdate rejects the changes of an update that the current state of transaction does not allow.
+func checkUpdate(transaction *entity.Transaction, currencyCode string, amount int64, status string) error {
+	currencyCode = currency.Normalize(currencyCode)
+	if currencyCode != "" && currencyCode != transaction.Currency {
+		return fmt.Errorf("%w: currency of a transaction cannot change", ErrInvalidInput)
+	}
+	if err := validateMoney(amount, transaction.Currency); err != nil {
+		return err
+	}
+	if claimed := transaction.RefundedAmount + transaction.ChargedBackAmount; amount < claimed {
+		return fmt.Errorf("%w: amount must not be less than the refunded and charged back amount %d", ErrInvalidInput, claimed)
+	}
+	if err := checkLineItemsTotal(transaction.LineItems, amount); err != nil {
+		return err
+	}
+	if err := checkAuthorizedUpdate(transaction, amount, status); err != nil {
+		return err
+	}
+	if err := checkReviewUpdate(transaction, amount, status); err != nil {
+		return err
+	}
+	if err := checkSplitUpdate(transaction, amount, status); err != nil {
+		return err
+	}
+	if managedStatuses[status] && status != transaction.Status.Name {
+		return fmt.Errorf("%w: %q is set by its own operation", ErrInvalidTransition, status)
+	}
+
+	return nil
+}
+
+// checkAuthorizedUpdate leaves an authorized transaction to capture and void, which keep the
+// captured and voided amounts in step with its status. It can still be deleted.
+func checkAuthorizedUpdate(transaction *entity.Transaction, amount int64, status string) error {
+	if transaction.AuthorizedAmount == 0 {
+		return nil
+	}
+	if amount != transaction.Amount {
+		return fmt.Errorf("%w: amount of an authorized transaction cannot change", ErrInvalidInput)
+	}
+	if status != "" && status != transaction.Status.Name && status != deletedStatus {
+		return fmt.Errorf("%w: status of an authorized transaction changes by capture or void", ErrInvalidTransition)
+	}
+
+	return nil
+}
+
+// checkReviewUpdate leaves a transaction held for review to approve and decline, which resolve its
+// risk assessment, so that it is not released or changed after it was screened.
+func checkReviewUpdate(transaction *entity.Transaction, amount int64, status string) error {
+	if transaction.Status.Name != reviewStatus {
+		return nil
+	}
+	if amount != transaction.Amount {
+		return fmt.Errorf("%w: amount of a transaction in review cannot change", ErrInvalidInput)
+	}
+	if status != "" && status != transaction.Status.Name {
+		return fmt.Errorf("%w: status of a transaction in review changes by approve or decline", ErrInvalidTransition)
+	}
+
+	return nil
+}
+
+// checkSplitUpdate keeps the amounts of a split transaction and its children in sync and the status
+// of the split transaction derived from them.
+func checkSplitUpdate(transaction *entity.Transaction, amount int64, status string) error {
+	if (transaction.SplitType != "" || transaction.ParentID != nil) && amount != transaction.Amount {
+		return fmt.Errorf("%w: amount of a split transaction or of its children cannot change", ErrInvalidInput)
+	}
+	if transaction.SplitType != "" && status != "" && status != transaction.Status.Name {
+		return fmt.Errorf("%w: status of a split transaction follows its children", ErrInvalidTransition)
+	}
+
+	return nil
+}
+
+func (s *TransactionService) Delete(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	var transaction *entity.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.findByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := s.mapper.ToDTO(transaction)
+
+		if err = s.transition(transaction, deletedStatus); err != nil {
+			return err
+		}
+
+		transaction, err = s.repository.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.recordChange(ctx, TransactionDeletedEvent, HistoryDeleted, before, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapper.ToDTO(transaction), nil
+}
+
+// Restore undoes the deletion of a transaction, moving it back to the status it had when it was
+// deleted.
+func (s *TransactionService) Restore(ctx context.Context, id uuid.UUID) (*dto.Transaction, error) {
+	var transaction *entity.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.repository.FindByIDWithDeleted(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTransactionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !transaction.DeletedAt.Valid {
+			return fmt.Errorf("%w: transaction is not deleted", ErrInvalidTransition)
+		}
+		before := s.mapper.ToDTO(transaction)
+
+		name, err := s.history.StatusBefore(ctx, id, HistoryDeleted)
+		if err != nil {
+			return err
+		}
+		status, err := findStatusByName(s.statusRepository, name)
+		if err != nil {
+			return err
+		}
+
+		transaction, err = s.repository.Restore(ctx, id, status)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: transaction is not deleted", ErrInvalidTransition)
+		}
+		if err != nil {
+			return err
+		}
+		return s.recordChange(ctx, TransactionRestoredEvent, HistoryRestored, before, transaction)
+	})
 	if err != nil {
 		return nil, err
 	}
+
 	return s.mapper.ToDTO(transaction), nil
 }
 
-func (s *TransactionService) Delete(id uuid.UUID) (*dto.Transaction, error) {
-	transaction, err := s.repository.Delete(id)
+// recordChange appends a change to the history, posts the money it moved to the ledger and
+// enqueues its notification. A status change of a child updates the status of its parent. Call it
+// inside the transaction that writes the change.
+func (s *TransactionService) recordChange(
+	ctx context.Context, eventType, action string, before *dto.Transaction, after *entity.Transaction) error {
+	afterDTO := s.mapper.ToDTO(after)
+	if err := s.history.Record(ctx, action, before, afterDTO); err != nil {
+		return err
+	}
+	if err := s.ledger.Post(ctx, eventType, before, afterDTO); err != nil {
+		return err
+	}
+	if err := s.outbox.Enqueue(ctx, after.ID, eventType, afterDTO); err != nil {
+		return err
+	}
+
+	if after.ParentID != nil && before != nil && before.Status != afterDTO.Status {
+		return s.deriveParent(ctx, *after.ParentID)
+	}
+	return nil
+}
+
+// change applies fn to the locked transaction id, moves it to the status fn returns and records
+// the change as eventType and action.
+func (s *TransactionService) change(
+	ctx context.Context, id uuid.UUID, eventType, action string, fn func(*entity.Transaction) (string, error),
+) (*dto.Transaction, error) {
+	var transaction *entity.Transaction
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = s.findByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := s.mapper.ToDTO(transaction)
+
+		status, err := fn(transaction)
+		if err != nil {
+			return err
+		}
+		if err = s.transition(transaction, status); err != nil {
+			return err
+		}
+		transaction.UpdatedAt = time.Now()
+
+		if err = s.repository.Update(ctx, transaction); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, eventType, action, before, transaction)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return s.mapper.ToDTO(transaction), nil
 }
+
+// applyRefund adds a succeeded refund of amount to a locked transaction and moves it to
+// partially_refunded, or refunded once the whole amount is refunded.
+func (s *TransactionService) applyRefund(ctx context.Context, transaction *entity.Transaction, amount int64) error {
+	before := s.mapper.ToDTO(transaction)
+
+	refundable := refundableAmount(transaction)
+	if transaction.RefundedAmount+amount > refundable {
+		return fmt.Errorf("%w: %d of %d is already refunded", ErrRefundExceedsAmount, transaction.RefundedAmount, refundable)
+	}
+	transaction.RefundedAmount += amount
+
+	status := partiallyRefundedStatus
+	if transaction.RefundedAmount == refundable {
+		status = refundedStatus
+	}
+	if err := s.transition(transaction, status); err != nil {
+		return err
+	}
+	transaction.UpdatedAt = time.Now()
+
+	if err := s.repository.Update(ctx, transaction); err != nil {
+		return err
+	}
+	return s.recordChange(ctx, TransactionUpdatedEvent, HistoryUpdated, before, transaction)
+}
+
+// checkParties rejects references to merchants and customers that do not exist.
+func (s *TransactionService) checkParties(ctx context.Context, transaction *entity.Transaction) error {
+	if transaction.MerchantID != nil {
+		_, err := s.merchants.FindByID(ctx, *transaction.MerchantID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownMerchant, transaction.MerchantID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if transaction.CustomerID != nil {
+		_, err := s.customers.FindByID(ctx, *transaction.CustomerID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownCustomer, transaction.CustomerID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findByID loads a transaction, reporting a missing one as ErrTransactionNotFound.
+func (s *TransactionService) findByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	transaction, err := s.repository.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+
+	return transaction, err
+}
+
+// findByIDForUpdate loads a transaction and locks its row until the surrounding transaction ends.
+func (s *TransactionService) findByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
+	transaction, err := s.repository.FindByIDForUpdate(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+
+	return transaction, err
+}
+
+// transition moves transaction to the status named name if the status graph allows it.
+// An empty name keeps the current status, which is only allowed while it is not terminal.
+func (s *TransactionService) transition(transaction *entity.Transaction, name string) error {
+	current, err := s.statusRepository.FindByID(transaction.StatusID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Rows written before statuses were persisted may not reference one.
+		return fmt.Errorf("%w: transaction has no known status", ErrInvalidTransition)
+	}
+	if err != nil {
+		return err
+	}
+
+	if name == "" || name == current.Name {
+		if len(current.Next) == 0 {
+			return fmt.Errorf("%w: %q is terminal", ErrInvalidTransition, current.Name)
+		}
+		return nil
+	}
+
+	next, err := findStatusByName(s.statusRepository, name)
+	if err != nil {
+		return err
+	}
+
+	if !canTransition(current, next.Name) {
+		return fmt.Errorf("%w: %q to %q", ErrInvalidTransition, current.Name, next.Name)
+	}
+
+	transaction.StatusID = next.ID
+	transaction.Status = *next
+
+	return nil
+}
+
+// refundableAmount is the gross amount of a transaction less what was charged back.
+func refundableAmount(transaction *entity.Transaction) int64 {
+	return grossAmount(transaction) - transaction.ChargedBackAmount
+}
+
+// grossAmount is the captured amount of an authorized transaction and the whole amount otherwise.
+func grossAmount(transaction *entity.Transaction) int64 {
+	if transaction.AuthorizedAmount > 0 {
+		return transaction.CapturedAmount
+	}
+
+	return transaction.Amount
+}
+
+func validateMoney(amount int64, currencyCode string) error {
+	if amount < 0 {
+		return fmt.Errorf("%w: amount must not be negative", ErrInvalidInput)
+	}
+	if err := currency.Validate(currencyCode); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/the-great-checkout/transactions-crud/internal/entity"
+)
+
+func TestCheckUpdate(t *testing.T) {
+	inStatus := func(status string) *entity.Transaction {
+		return &entity.Transaction{Status: entity.Status{Name: status}, Amount: 1000, Currency: "USD"}
+	}
+
+	tests := []struct {
+		name        string
+		transaction *entity.Transaction
+		amount      int64
+		status      string
+		want        error
+	}{
+		{"created to completed", inStatus(createdStatus), 1000, completedStatus, nil},
+		{"created amount change", inStatus(createdStatus), 1500, "", nil},
+		{"created to managed status", inStatus(createdStatus), 1000, refundedStatus, ErrInvalidTransition},
+		{"review kept", inStatus(reviewStatus), 1000, reviewStatus, nil},
+		{"review metadata only", inStatus(reviewStatus), 1000, "", nil},
+		{"review released to created", inStatus(reviewStatus), 1000, createdStatus, ErrInvalidTransition},
+		{"review to completed", inStatus(reviewStatus), 1000, completedStatus, ErrInvalidTransition},
+		{"review to deleted", inStatus(reviewStatus), 1000, deletedStatus, ErrInvalidTransition},
+		{"review amount change", inStatus(reviewStatus), 1500, "", ErrInvalidInput},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkUpdate(test.transaction, "", test.amount, test.status)
+			if test.want == nil && err != nil {
+				t.Fatalf("checkUpdate() = %v, want nil", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Fatalf("checkUpdate() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Periodic runs a task on a fixed interval until its context is cancelled.
+type Periodic struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+}
+
+func NewPeriodic(name string, interval time.Duration, task func(ctx context.Context) error) *Periodic {
+	return &Periodic{
+		name:     name,
+		interval: interval,
+		task:     task,
+	}
+}
+
+// Run blocks until ctx is done. Failed runs are logged and retried on the next tick.
+func (p *Periodic) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.task(ctx); err != nil {
+				log.Printf("%s: %v", p.name, err)
+			}
+		}
+	}
+}
@@ -1,16 +1,15 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/Netflix/go-env"
-	"github.com/labstack/echo/v4"
-	echoSwagger "github.com/swaggo/echo-swagger"
 	_ "github.com/the-great-checkout/transactions-crud/docs"
-
-	"github.com/the-great-checkout/transactions-crud/internal/controller"
-	"github.com/the-great-checkout/transactions-crud/internal/database"
-	"github.com/the-great-checkout/transactions-crud/internal/mapper"
-	"github.com/the-great-checkout/transactions-crud/internal/repository"
-	"github.com/the-great-checkout/transactions-crud/internal/service"
 )
 
 type Environment struct {
@@ -24,11 +23,74 @@ type Environment struct {
 		Collection string `env:"MONGO_COLLECTION,default=transactions"`
 	}
 
+	Migration struct {
+		LegacyCurrency string `env:"MIGRATION_LEGACY_CURRENCY,default=USD"`
+	}
+
 	Kafka struct {
 		Topic   string `env:"KAFKA_TOPIC,default=transactions"`
 		Address string `env:"KAFKA_ADDRESS,default=localhost:9092"`
 	}
 
+	Outbox struct {
+		RelayInterval time.Duration `env:"OUTBOX_RELAY_INTERVAL,default=1s"`
+		Retention     time.Duration `env:"OUTBOX_RETENTION,default=168h"`
+	}
+
+	Idempotency struct {
+		Retention     time.Duration `env:"IDEMPOTENCY_RETENTION,default=24h"`
+		Lease         time.Duration `env:"IDEMPOTENCY_LEASE,default=1m"`
+		PurgeInterval time.Duration `env:"IDEMPOTENCY_PURGE_INTERVAL,default=1h"`
+	}
+
+	Projection struct {
+		Interval time.Duration `env:"PROJECTION_INTERVAL,default=1s"`
+	}
+
+	Authorization struct {
+		TTL            time.Duration `env:"AUTHORIZATION_TTL,default=168h"`
+		ExpiryInterval time.Duration `env:"AUTHORIZATION_EXPIRY_INTERVAL,default=1m"`
+	}
+
+	Dispute struct {
+		EvidenceWindow time.Duration `env:"DISPUTE_EVIDENCE_WINDOW,default=168h"`
+		ExpiryInterval time.Duration `env:"DISPUTE_EXPIRY_INTERVAL,default=1m"`
+	}
+
+	Settlement struct {
+		Interval time.Duration `env:"SETTLEMENT_INTERVAL,default=5m"`
+	}
+
+	Expiry struct {
+		TTL      time.Duration `env:"EXPIRY_TTL,default=72h"`
+		Statuses []string      `env:"EXPIRY_STATUSES,default=pending"`
+		Interval time.Duration `env:"EXPIRY_INTERVAL,default=5m"`
+	}
+
+	Purge struct {
+		Retention time.Duration `env:"PURGE_RETENTION,default=2160h"`
+	}
+
+	Schedule struct {
+		Interval time.Duration `env:"SCHEDULE_INTERVAL,default=1m"`
+	}
+
+	Ledger struct {
+		CheckInterval time.Duration `env:"LEDGER_CHECK_INTERVAL,default=1h"`
+	}
+
+	FX struct {
+		RatesFile          string `env:"FX_RATES_FILE"`
+		SettlementCurrency string `env:"FX_SETTLEMENT_CURRENCY"`
+	}
+
+	Risk struct {
+		ReviewScore  int `env:"RISK_REVIEW_SCORE,default=50"`
+		DeclineScore int `env:"RISK_DECLINE_SCORE,default=80"`
+	}
+
+	AdminToken string `env:"ADMIN_TOKEN"`
+
 	Port string `env:"PORT,default=:8081"`
 }
 
@@ -40,39 +102,24 @@ type Environment struct {
 
 func main() {
 	var environment Environment
-	_, err := env.UnmarshalFromEnviron(&environment)
-	if err != nil {
+	if _, err := env.UnmarshalFromEnviron(&environment); err != nil {
 		panic(err)
 	}
 
-	mongo := database.NewMongo(environment.Mongo.URI, environment.Mongo.Database, environment.Mongo.Collection)
-	postgres := database.NewPostgres(environment.Postgres.DSN, environment.Postgres.Schema)
-
-	notificationService := service.NewNotificationService(environment.Kafka.Topic, environment.Kafka.Address)
-
-	transactionRepository := repository.NewTransactionRepository(postgres, mongo)
-	transactionMapper := mapper.NewTransactionMapper()
-	transactionService := service.NewTransactionService(transactionRepository, transactionMapper)
-	transactionController := controller.NewTransactionController(transactionService, notificationService)
-
-	statusRepository := repository.NewStatusRepository(postgres)
-	statusMapper := mapper.NewStatusMapper()
-	statusService := service.NewStatusService(statusRepository, statusMapper)
-	statusController := controller.NewStatusController(statusService)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
-	e := echo.New()
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	app := newApplication(environment)
 
-	v1 := e.Group("/v1")
-
-	v1.POST("/transactions", transactionController.CreateHandler)
-	v1.GET("/transactions/:transactionID", transactionController.GetByIDHandler)
-	v1.GET("/transactions", transactionController.GetAllHandler)
-	v1.PUT("/transactions/:transactionID", transactionController.UpdateHandler)
-	v1.DELETE("/transactions/:transactionID", transactionController.DeleteHandler)
-	v1.POST("/statuses", statusController.CreateHandler)
-	v1.GET("/statuses/:statusID", statusController.GetByIDHandler)
-	v1.GET("/statuses", statusController.GetAllHandler)
+	var err error
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		err = reconcileCommand(ctx, os.Args[2:], app.reconciliationService)
+	} else {
+		err = app.serve(ctx)
+	}
 
-	e.Logger.Fatal(e.Start(environment.Port))
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
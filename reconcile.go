@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/the-great-checkout/transactions-crud/internal/dto"
+)
+
+const defaultReconcileBatchSize = 500
+
+type reconciler interface {
+	Reconcile(ctx context.Context, batchSize int, repair bool, report func(dto.Discrepancy) error) (*dto.ReconciliationSummary, error)
+}
+
+// reconcileCommand runs the reconcile subcommand:
+//
+//	transactions-crud reconcile [-format json|csv] [-batch-size n] [-output file] [-repair]
+func reconcileCommand(ctx context.Context, args []string, service reconciler) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	format := flags.String("format", "json", "report format, json or csv")
+	batchSize := flags.Int("batch-size", defaultReconcileBatchSize, "rows and documents read per batch")
+	output := flags.String("output", "", "report file, standard output when empty")
+	repair := flags.Bool("repair", false, "rewrite drifted Mongo documents from Postgres")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *batchSize <= 0 {
+		return errors.New("batch-size must be positive")
+	}
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	if *output == "" {
+		return reconcile(ctx, service, newReportWriter(*format, os.Stdout), *batchSize, *repair)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	err = reconcile(ctx, service, newReportWriter(*format, file), *batchSize, *repair)
+	return errors.Join(err, file.Close())
+}
+
+func reconcile(ctx context.Context, service reconciler, writer reportWriter, batchSize int, repair bool) error {
+	if err := writer.Begin(); err != nil {
+		return err
+	}
+
+	summary, err := service.Reconcile(ctx, batchSize, repair, writer.Write)
+	if summary != nil {
+		if endErr := writer.End(summary); endErr != nil && err == nil {
+			err = endErr
+		}
+		fmt.Fprintf(os.Stderr, "scanned %d rows and %d documents: %d missing, %d extra, %d mismatched, %d repaired\n",
+			summary.ScannedRows, summary.ScannedDocuments, summary.Missing, summary.Extra, summary.Mismatched, summary.Repaired)
+	}
+
+	return err
+}
+
+type reportWriter interface {
+	Begin() error
+	Write(discrepancy dto.Discrepancy) error
+	End(summary *dto.ReconciliationSummary) error
+}
+
+func newReportWriter(format string, out io.Writer) reportWriter {
+	if format == "csv" {
+		return &csvReportWriter{out: csv.NewWriter(out)}
+	}
+
+	return &jsonReportWriter{out: out}
+}
+
+// jsonReportWriter streams {"discrepancies": [...], "summary": {...}}.
+type jsonReportWriter struct {
+	out     io.Writer
+	written int
+}
+
+func (w *jsonReportWriter) Begin() error {
+	_, err := io.WriteString(w.out, `{"discrepancies":[`)
+	return err
+}
+
+func (w *jsonReportWriter) Write(discrepancy dto.Discrepancy) error {
+	data, err := json.Marshal(discrepancy)
+	if err != nil {
+		return err
+	}
+
+	if w.written > 0 {
+		if _, err = io.WriteString(w.out, ","); err != nil {
+			return err
+		}
+	}
+	w.written++
+
+	_, err = w.out.Write(data)
+	return err
+}
+
+func (w *jsonReportWriter) End(summary *dto.ReconciliationSummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w.out, `],"summary":%s}`+"\n", data)
+	return err
+}
+
+// csvReportWriter writes one discrepancy per row. The summary goes to standard error only.
+type csvReportWriter struct {
+	out *csv.Writer
+}
+
+func (w *csvReportWriter) Begin() error {
+	return w.out.Write([]string{"transaction_id", "kind", "fields", "repaired"})
+}
+
+func (w *csvReportWriter) Write(discrepancy dto.Discrepancy) error {
+	return w.out.Write([]string{
+		discrepancy.TransactionID.String(),
+		discrepancy.Kind,
+		strings.Join(discrepancy.Fields, ";"),
+		strconv.FormatBool(discrepancy.Repaired),
+	})
+}
+
+func (w *csvReportWriter) End(*dto.ReconciliationSummary) error {
+	w.out.Flush()
+	return w.out.Error()
+}